@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -12,15 +13,29 @@ import (
 
 	"github.com/tmaxmax/bmoisil/pkg/toolchain"
 	_ "github.com/tmaxmax/bmoisil/pkg/toolchain/gcc"
+	_ "github.com/tmaxmax/bmoisil/pkg/toolchain/valgrind"
 )
 
 func main() {
-	if err := run(); err != nil {
+	useSession := flag.Bool("session", false, "Open a GDB/MI debug session instead of an interactive debugger")
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	var err error
+	if *useSession {
+		err = runSession(ctx)
+	} else {
+		err = run(ctx)
+	}
+
+	if err != nil {
 		log.Fatalln(err)
 	}
 }
 
-func run() error {
+func run(ctx context.Context) error {
 	compiler, err := toolchain.UsePreferredCompiler()
 	if err != nil {
 		return err
@@ -30,9 +45,6 @@ func run() error {
 
 	fmt.Printf("Compiler: %s\nPath: %s\nVersion: %s\n\n", info.Name, info.Path, info.Version)
 
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer cancel()
-
 	filepath := "./compiled"
 	if runtime.GOOS == "windows" {
 		filepath += ".exe"
@@ -62,5 +74,95 @@ func run() error {
 	dinfo := debugger.Info()
 	fmt.Printf("Debugger: %s\nPath: %s\nVersion: %s\n\n", dinfo.Name, dinfo.Path, dinfo.Version)
 
-	return debugger.Debug(ctx, filepath, nil)
+	if err := debugger.Debug(ctx, filepath, nil); err != nil {
+		return err
+	}
+
+	return memcheck(ctx, info.RecommendedMemoryChecker, filepath)
+}
+
+// runSession compiles the program read from stdin, opens a GDB/MI debug session for it,
+// sets a breakpoint at main, runs the program and prints the locals at the first stop.
+// It exists as a smoke test for DebugSession.
+func runSession(ctx context.Context) error {
+	compiler, err := toolchain.UsePreferredCompiler()
+	if err != nil {
+		return err
+	}
+
+	info := compiler.Info()
+
+	filepath := "./compiled"
+	if runtime.GOOS == "windows" {
+		filepath += ".exe"
+	}
+
+	if err := compiler.Compile(ctx, os.Stdin, filepath, &toolchain.CompileOptions{
+		OptimizationLevel: toolchain.CompileOptimizationDebug,
+		LanguageStandard:  toolchain.CompileLanguageStandardCPP11,
+	}); err != nil {
+		return err
+	}
+	defer os.Remove(filepath)
+
+	debugger, err := toolchain.NewDebugger(info.RecommendedDebugger)
+	if err != nil {
+		return err
+	}
+
+	session, err := debugger.NewSession(ctx, filepath)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	if _, err := session.SetBreakpoint(ctx, "main"); err != nil {
+		return err
+	}
+
+	if err := session.Run(ctx, nil, nil); err != nil {
+		return err
+	}
+
+	for event := range session.Events() {
+		if event.Kind != toolchain.DebugEventStopped {
+			continue
+		}
+
+		locals, err := session.ReadLocals(ctx)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Stopped at %s: %+v\n", event.Reason, locals)
+
+		return nil
+	}
+
+	return fmt.Errorf("debug session ended before the program stopped")
+}
+
+// memcheck runs the compiled program through the given memory checker, if one is available,
+// and prints a summary of the errors and leaks it found.
+func memcheck(ctx context.Context, name string, executablePath string) error {
+	checker, err := toolchain.NewMemoryChecker(name)
+	if err != nil {
+		fmt.Printf("Memory checker unavailable, skipping: %v\n", err)
+		return nil
+	}
+
+	cinfo := checker.Info()
+	fmt.Printf("Memory checker: %s\nPath: %s\nVersion: %s\n\n", cinfo.Name, cinfo.Path, cinfo.Version)
+
+	report, err := checker.Check(ctx, executablePath, &toolchain.CheckOptions{
+		Stdin:     os.Stdin,
+		LeakCheck: toolchain.LeakCheckFull,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Exit code: %d\nErrors found: %d\nBytes leaked: %d\n", report.ExitCode, len(report.Errors), report.Leak.DefinitelyLost)
+
+	return nil
 }
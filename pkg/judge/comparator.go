@@ -0,0 +1,72 @@
+package judge
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// A Comparator decides whether a solution's actual output matches the expected output
+// of a test case.
+type Comparator interface {
+	Compare(expected, actual []byte) bool
+}
+
+// ComparatorFunc adapts a function to a Comparator.
+type ComparatorFunc func(expected, actual []byte) bool
+
+func (f ComparatorFunc) Compare(expected, actual []byte) bool { return f(expected, actual) }
+
+// Exact requires the actual output to match the expected output byte for byte.
+var Exact Comparator = ComparatorFunc(func(expected, actual []byte) bool {
+	return bytes.Equal(expected, actual)
+})
+
+// WhitespaceInsensitive compares the expected and actual output token by token, ignoring
+// the amount and kind of whitespace that separates them.
+var WhitespaceInsensitive Comparator = ComparatorFunc(func(expected, actual []byte) bool {
+	return tokensEqual(string(expected), string(actual), func(a, b string) bool { return a == b })
+})
+
+// FloatTolerant returns a Comparator that compares the expected and actual output token by
+// token like WhitespaceInsensitive, but treats tokens that parse as floating-point numbers
+// as equal if they are within tolerance of each other.
+func FloatTolerant(tolerance float64) Comparator {
+	return ComparatorFunc(func(expected, actual []byte) bool {
+		return tokensEqual(string(expected), string(actual), func(a, b string) bool {
+			if a == b {
+				return true
+			}
+
+			af, aerr := strconv.ParseFloat(a, 64)
+			bf, berr := strconv.ParseFloat(b, 64)
+			if aerr != nil || berr != nil {
+				return false
+			}
+
+			diff := af - bf
+			if diff < 0 {
+				diff = -diff
+			}
+
+			return diff <= tolerance
+		})
+	})
+}
+
+func tokensEqual(expected, actual string, eq func(a, b string) bool) bool {
+	expectedTokens := strings.Fields(expected)
+	actualTokens := strings.Fields(actual)
+
+	if len(expectedTokens) != len(actualTokens) {
+		return false
+	}
+
+	for i, t := range expectedTokens {
+		if !eq(t, actualTokens[i]) {
+			return false
+		}
+	}
+
+	return true
+}
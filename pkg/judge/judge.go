@@ -0,0 +1,209 @@
+/*
+Package judge grades a compiled solution against a pbinfo.Problem's test cases,
+enforcing the problem's time and memory limits and reporting a Verdict for
+every case.
+*/
+package judge
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tmaxmax/bmoisil/pkg/pbinfo"
+	"github.com/tmaxmax/bmoisil/pkg/toolchain"
+)
+
+// Result classifies the outcome of grading a single test case.
+type Result int
+
+const (
+	// Accepted means the solution's output matched the expected output within the limits.
+	Accepted Result = iota
+	// WrongAnswer means the solution ran within the limits but produced the wrong output.
+	WrongAnswer
+	// TimeLimitExceeded means the solution did not finish within Problem.MaxTime.
+	TimeLimitExceeded
+	// MemoryLimitExceeded means the solution's peak resident set size exceeded Problem.MaxMemoryBytes.
+	// A solution that overflows Problem.MaxStackBytes crashes, and is reported as RuntimeError.
+	MemoryLimitExceeded
+	// RuntimeError means the solution exited with a non-zero status or crashed.
+	RuntimeError
+	// CompilationError means the solution could not be compiled; reserved for callers
+	// that compile lazily per case.
+	CompilationError
+	// MemoryCheckFailed means the solution ran correctly but a MemoryChecker found errors or leaks.
+	MemoryCheckFailed
+)
+
+func (r Result) String() string {
+	switch r {
+	case Accepted:
+		return "Accepted"
+	case WrongAnswer:
+		return "Wrong Answer"
+	case TimeLimitExceeded:
+		return "Time Limit Exceeded"
+	case MemoryLimitExceeded:
+		return "Memory Limit Exceeded"
+	case RuntimeError:
+		return "Runtime Error"
+	case CompilationError:
+		return "Compilation Error"
+	case MemoryCheckFailed:
+		return "Memory Check Failed"
+	default:
+		return "Unknown"
+	}
+}
+
+// Verdict is the outcome of grading a single test case.
+type Verdict struct {
+	// Case is the index of the test case this verdict is for, within the slice passed to Run.
+	Case int
+	// Result classifies the outcome.
+	Result Result
+	// Message gives more details about the outcome, e.g. the reason a solution crashed.
+	Message string
+	// Time is the wall-clock time the solution took to run.
+	Time time.Duration
+	// CPUTime is the CPU time the solution used, as reported by the OS.
+	CPUTime time.Duration
+	// MemoryBytes is the peak resident set size used by the solution.
+	MemoryBytes int64
+	// Score awarded for this case. Defaults to TestCase.Score if the verdict is Accepted, 0 otherwise.
+	Score int
+	// MemoryErrors found by the configured MemoryChecker, if checking was enabled and the case
+	// would otherwise have been Accepted.
+	MemoryErrors int
+}
+
+// A Judge grades a compiled solution against a Problem's test cases.
+type Judge struct {
+	// Comparator decides whether a solution's output matches a test case's expected output.
+	// Defaults to Exact.
+	Comparator Comparator
+	// MemoryChecker, if non-nil, additionally runs every case that would otherwise be Accepted
+	// through it and reports leaks and errors as a MemoryCheckFailed verdict instead. Cases
+	// with any other verdict are not checked.
+	MemoryChecker toolchain.MemoryChecker
+	// CheckOptions customizes the MemoryChecker run, if one is configured.
+	CheckOptions *toolchain.CheckOptions
+}
+
+// Run grades the executable at exePath against every one of the given test cases, in order,
+// honoring the problem's time and memory limits and the way it passes input/output.
+func (j *Judge) Run(ctx context.Context, exePath string, problem *pbinfo.Problem, cases []pbinfo.TestCase) ([]Verdict, error) {
+	comparator := j.Comparator
+	if comparator == nil {
+		comparator = Exact
+	}
+
+	verdicts := make([]Verdict, len(cases))
+
+	for i, tc := range cases {
+		v, err := j.runCase(ctx, exePath, problem, tc, comparator)
+		if err != nil {
+			return verdicts[:i], fmt.Errorf("judge: case %d: %w", i, err)
+		}
+
+		v.Case = i
+		verdicts[i] = v
+	}
+
+	return verdicts, nil
+}
+
+func (j *Judge) runCase(ctx context.Context, exePath string, problem *pbinfo.Problem, tc pbinfo.TestCase, comparator Comparator) (Verdict, error) {
+	runner := j.runner(problem, comparator)
+
+	result, stats, workDir, err := runner.RunKeepDir(ctx, exePath, tc)
+	if workDir != "" {
+		defer os.RemoveAll(workDir)
+	}
+	if err != nil {
+		return Verdict{}, err
+	}
+
+	v := Verdict{
+		Time:        stats.WallTime,
+		CPUTime:     stats.CPUTime,
+		MemoryBytes: stats.MemoryBytes,
+	}
+
+	switch result {
+	case toolchain.TLE:
+		v.Result = TimeLimitExceeded
+		return v, nil
+	case toolchain.MLE:
+		v.Result = MemoryLimitExceeded
+		return v, nil
+	case toolchain.RE:
+		v.Result = RuntimeError
+		return v, nil
+	case toolchain.WA:
+		v.Result = WrongAnswer
+		return v, nil
+	}
+
+	v.Result = Accepted
+	v.Score = tc.Score
+
+	if j.MemoryChecker != nil {
+		report, err := j.MemoryChecker.Check(ctx, exePath, j.checkOptions(tc, workDir))
+		if err != nil {
+			return Verdict{}, fmt.Errorf("memory checker: %w", err)
+		}
+
+		v.MemoryErrors = len(report.Errors)
+		switch {
+		case report.ExitCode != 0:
+			// The checked run itself failed (e.g. it couldn't open its input), which makes any
+			// absence of reported errors meaningless: don't let that read as a clean Accepted.
+			v.Result = RuntimeError
+			v.Score = 0
+		case v.MemoryErrors > 0 || report.Leak.DefinitelyLost > 0:
+			v.Result = MemoryCheckFailed
+			v.Score = 0
+		}
+	}
+
+	return v, nil
+}
+
+// runner builds the toolchain.Runner that enforces problem's limits and input/output
+// conventions, comparing a case's actual output to its expected output with comparator.
+func (j *Judge) runner(problem *pbinfo.Problem, comparator Comparator) *toolchain.Runner {
+	runner := &toolchain.Runner{
+		MaxTime:        problem.MaxTime,
+		MaxMemoryBytes: problem.MaxMemoryBytes,
+		MaxStackBytes:  problem.MaxStackBytes,
+		InputFromStdin: problem.InputFromStdin(),
+		InputRelPath:   problem.Input,
+		Compare:        comparator.Compare,
+	}
+
+	if !problem.OutputToStdout() {
+		runner.OutputRelPath = problem.Output
+	}
+
+	return runner
+}
+
+func (j *Judge) checkOptions(tc pbinfo.TestCase, workDir string) *toolchain.CheckOptions {
+	opts := toolchain.CheckOptions{LeakCheck: toolchain.LeakCheckFull}
+	if j.CheckOptions != nil {
+		opts = *j.CheckOptions
+	}
+
+	if opts.Stdin == nil {
+		opts.Stdin = bytes.NewReader(tc.Input)
+	}
+	if opts.Dir == "" {
+		opts.Dir = workDir
+	}
+
+	return &opts
+}
@@ -0,0 +1,135 @@
+package pbinfo
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheEntry is a single cached HTTP response, as stored and retrieved by a Cache
+// implementation.
+type CacheEntry struct {
+	Body []byte
+	// ETag and LastModified, when set, let Client revalidate an expired entry with a
+	// conditional GET instead of re-fetching it outright.
+	ETag         string
+	LastModified string
+	// Expires is when the entry should no longer be served without revalidation. The zero
+	// value means "unknown freshness": Client will always revalidate or refetch.
+	Expires time.Time
+}
+
+// Cache stores and retrieves the HTTP responses a Client fetches, keyed by request URL
+// (including its query string). Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the entry cached under key, and whether one was found.
+	Get(ctx context.Context, key string) (entry CacheEntry, ok bool, err error)
+	// Put stores entry under key, replacing whatever was cached there before.
+	Put(ctx context.Context, key string, entry CacheEntry) error
+	// Delete removes the entry cached under key, if any.
+	Delete(ctx context.Context, key string) error
+}
+
+// CacheTTLs configures how long Client treats a cached response as fresh when the server sends
+// no Cache-Control max-age. Once that time has passed, an entry with an ETag or Last-Modified is
+// revalidated with a conditional GET, and one without is refetched. Zero fields fall back to the
+// matching field of DefaultCacheTTLs.
+type CacheTTLs struct {
+	// ProblemPage is the TTL for a problem's detail page, which rarely changes once published.
+	ProblemPage time.Duration
+	// TestCases is the TTL for a problem's test case listing, which its author can still edit.
+	TestCases time.Duration
+}
+
+// DefaultCacheTTLs is used by Client whenever its CacheTTLs field is the zero value. Test case
+// downloads aren't listed here: they're content-addressed by problem and case ID, so Client
+// caches them indefinitely regardless of CacheTTLs.
+var DefaultCacheTTLs = CacheTTLs{
+	ProblemPage: 24 * time.Hour,
+	TestCases:   5 * time.Minute,
+}
+
+func (c *Client) cacheTTLs() CacheTTLs {
+	ttls := c.CacheTTLs
+	if ttls.ProblemPage == 0 {
+		ttls.ProblemPage = DefaultCacheTTLs.ProblemPage
+	}
+	if ttls.TestCases == 0 {
+		ttls.TestCases = DefaultCacheTTLs.TestCases
+	}
+	return ttls
+}
+
+// cacheKind classifies a request URL by the kind of resource it fetches, so Client knows which
+// CacheTTLs field (if any) governs its freshness.
+type cacheKind int
+
+const (
+	cacheKindDefault cacheKind = iota
+	cacheKindProblemPage
+	cacheKindTestCases
+	cacheKindDownload
+)
+
+func classifyCacheKind(u *url.URL) cacheKind {
+	switch {
+	case strings.HasPrefix(u.Path, "/probleme/"):
+		return cacheKindProblemPage
+	case strings.HasSuffix(u.Path, "/ajx-problema-afisare-teste.php"):
+		return cacheKindTestCases
+	case strings.HasSuffix(u.Path, "/descarca-test.php"):
+		return cacheKindDownload
+	default:
+		return cacheKindDefault
+	}
+}
+
+func (c *Client) fresh(kind cacheKind, entry CacheEntry) bool {
+	if kind == cacheKindDownload {
+		return true
+	}
+	return !entry.Expires.IsZero() && time.Now().Before(entry.Expires)
+}
+
+func (c *Client) expiry(kind cacheKind, header http.Header) time.Time {
+	if kind == cacheKindDownload {
+		// "Forever": fresh always returns true for this kind instead of consulting Expires.
+		return time.Time{}
+	}
+
+	if age, ok := maxAge(header.Get("Cache-Control")); ok {
+		return time.Now().Add(age)
+	}
+
+	ttls := c.cacheTTLs()
+
+	switch kind {
+	case cacheKindProblemPage:
+		return time.Now().Add(ttls.ProblemPage)
+	case cacheKindTestCases:
+		return time.Now().Add(ttls.TestCases)
+	default:
+		return time.Time{}
+	}
+}
+
+func maxAge(cacheControl string) (time.Duration, bool) {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.TrimSpace(directive)
+		if !strings.HasPrefix(directive, "max-age=") {
+			continue
+		}
+
+		seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+		if err != nil {
+			continue
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,166 @@
+/*
+Package fs provides a pbinfo.Cache implementation that stores entries as gzip-compressed files
+on disk, defaulting to the user's XDG cache directory.
+*/
+package fs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tmaxmax/bmoisil/pkg/pbinfo"
+)
+
+// Cache is a pbinfo.Cache that stores its entries as files under Dir: one gzip-compressed body
+// file and one JSON metadata file per cache key.
+type Cache struct {
+	// Dir is the directory entries are stored under. It is created on first Put if it doesn't
+	// already exist.
+	Dir string
+}
+
+var _ pbinfo.Cache = (*Cache)(nil)
+
+// New returns a Cache that stores its entries under the "bmoisil/pbinfo" subdirectory of the
+// user's cache directory (see os.UserCacheDir).
+func New() (*Cache, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("fs: failed to determine cache directory: %w", err)
+	}
+
+	return &Cache{Dir: filepath.Join(dir, "bmoisil", "pbinfo")}, nil
+}
+
+type entryMeta struct {
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	Expires      time.Time `json:"expires,omitempty"`
+}
+
+func (c *Cache) paths(key string) (metaPath, bodyPath string) {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(c.Dir, name+".json"), filepath.Join(c.Dir, name+".gz")
+}
+
+func (c *Cache) Get(_ context.Context, key string) (pbinfo.CacheEntry, bool, error) {
+	metaPath, bodyPath := c.paths(key)
+
+	rawMeta, err := os.ReadFile(metaPath)
+	if os.IsNotExist(err) {
+		return pbinfo.CacheEntry{}, false, nil
+	}
+	if err != nil {
+		return pbinfo.CacheEntry{}, false, fmt.Errorf("fs: failed to read cache metadata for %q: %w", key, err)
+	}
+
+	var meta entryMeta
+	if err := json.Unmarshal(rawMeta, &meta); err != nil {
+		return pbinfo.CacheEntry{}, false, fmt.Errorf("fs: failed to parse cache metadata for %q: %w", key, err)
+	}
+
+	gzBody, err := os.Open(bodyPath)
+	if err != nil {
+		return pbinfo.CacheEntry{}, false, fmt.Errorf("fs: failed to open cached body for %q: %w", key, err)
+	}
+	defer gzBody.Close()
+
+	gzReader, err := gzip.NewReader(gzBody)
+	if err != nil {
+		return pbinfo.CacheEntry{}, false, fmt.Errorf("fs: failed to decompress cached body for %q: %w", key, err)
+	}
+	defer gzReader.Close()
+
+	body, err := io.ReadAll(gzReader)
+	if err != nil {
+		return pbinfo.CacheEntry{}, false, fmt.Errorf("fs: failed to read cached body for %q: %w", key, err)
+	}
+
+	return pbinfo.CacheEntry{
+		Body:         body,
+		ETag:         meta.ETag,
+		LastModified: meta.LastModified,
+		Expires:      meta.Expires,
+	}, true, nil
+}
+
+func (c *Cache) Put(_ context.Context, key string, entry pbinfo.CacheEntry) error {
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return fmt.Errorf("fs: failed to create cache directory: %w", err)
+	}
+
+	rawMeta, err := json.Marshal(entryMeta{
+		ETag:         entry.ETag,
+		LastModified: entry.LastModified,
+		Expires:      entry.Expires,
+	})
+	if err != nil {
+		return fmt.Errorf("fs: failed to encode cache metadata for %q: %w", key, err)
+	}
+
+	var gzBody bytes.Buffer
+	gzWriter := gzip.NewWriter(&gzBody)
+	if _, err := gzWriter.Write(entry.Body); err != nil {
+		return fmt.Errorf("fs: failed to compress cached body for %q: %w", key, err)
+	}
+	if err := gzWriter.Close(); err != nil {
+		return fmt.Errorf("fs: failed to compress cached body for %q: %w", key, err)
+	}
+
+	metaPath, bodyPath := c.paths(key)
+
+	// Write the body before the metadata, each via a temp file renamed into place, so a
+	// concurrent Get never observes a torn write, and a crash between the two renames can only
+	// ever leave a body file with no (or stale) metadata, never the other way around.
+	if err := writeFileAtomic(c.Dir, bodyPath, gzBody.Bytes()); err != nil {
+		return fmt.Errorf("fs: failed to write cached body for %q: %w", key, err)
+	}
+	if err := writeFileAtomic(c.Dir, metaPath, rawMeta); err != nil {
+		return fmt.Errorf("fs: failed to write cache metadata for %q: %w", key, err)
+	}
+
+	return nil
+}
+
+// writeFileAtomic writes data to a temp file under dir, then renames it to path, so readers of
+// path never see a partially-written file.
+func writeFileAtomic(dir, path string, data []byte) error {
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (c *Cache) Delete(_ context.Context, key string) error {
+	metaPath, bodyPath := c.paths(key)
+
+	if err := os.Remove(metaPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("fs: failed to delete cache metadata for %q: %w", key, err)
+	}
+	if err := os.Remove(bodyPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("fs: failed to delete cached body for %q: %w", key, err)
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package pbinfo
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -26,28 +27,125 @@ const (
 // The Client is used to retrieve data from the PbInfo platform.
 type Client struct {
 	// The HTTP client to use. Defaults to http.DefaultClient.
-	Client     *http.Client
+	Client *http.Client
+
+	// Cache, if set, stores and serves the responses to GET requests, so repeated or resumed
+	// fetches (e.g. a long IterateProblems walk) don't always hit the network. See the
+	// pbinfo/cache packages for ready-made implementations.
+	Cache Cache
+	// CacheTTLs configures how long cached responses stay fresh when the server itself sends
+	// no Cache-Control max-age. The zero value falls back to DefaultCacheTTLs. Has no effect
+	// unless Cache is set.
+	CacheTTLs CacheTTLs
+	// RateLimit bounds how hard Client hits a single host, independently of Cache. The zero
+	// value falls back to its own defaults.
+	RateLimit RateLimit
+
 	clientInit sync.Once
 }
 
-func (c *Client) request(req *http.Request) (*http.Response, error) {
+func (c *Client) init() {
 	c.clientInit.Do(func() {
 		if c.Client == nil {
 			c.Client = http.DefaultClient
 		}
+
+		// Build a new *http.Client rather than mutating the caller's (which may be
+		// http.DefaultClient) so the rate limiting here is never visible outside this Client.
+		wrapped := *c.Client
+		wrapped.Transport = newRateLimitedTransport(c.Client.Transport, c.RateLimit)
+		c.Client = &wrapped
 	})
+}
+
+func (c *Client) request(req *http.Request) (*http.Response, error) {
+	c.init()
+
+	key := req.URL.String()
+	kind := classifyCacheKind(req.URL)
+
+	var cached *CacheEntry
+	if c.Cache != nil {
+		if entry, ok, err := c.Cache.Get(req.Context(), key); err == nil && ok {
+			cached = &entry
+			if c.fresh(kind, entry) {
+				return cachedResponse(req, entry), nil
+			}
+			if entry.ETag != "" {
+				req.Header.Set("If-None-Match", entry.ETag)
+			}
+			if entry.LastModified != "" {
+				req.Header.Set("If-Modified-Since", entry.LastModified)
+			}
+		}
+	}
 
 	res, err := c.Client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to do request to %q: %w", req.URL, err)
 	}
+
+	if cached != nil && res.StatusCode == http.StatusNotModified {
+		res.Body.Close()
+
+		// The body is still good, but the validators and freshness window may not be: a 304
+		// can carry a refreshed ETag/Last-Modified/Cache-Control, and even when it doesn't,
+		// revalidating resets this entry's TTL so it doesn't stay stuck re-validating forever.
+		refreshed := *cached
+		if etag := res.Header.Get("ETag"); etag != "" {
+			refreshed.ETag = etag
+		}
+		if lastModified := res.Header.Get("Last-Modified"); lastModified != "" {
+			refreshed.LastModified = lastModified
+		}
+		refreshed.Expires = c.expiry(kind, res.Header)
+
+		if err := c.Cache.Put(req.Context(), key, refreshed); err != nil {
+			return nil, fmt.Errorf("failed to cache response for %q: %w", req.URL, err)
+		}
+
+		return cachedResponse(req, refreshed), nil
+	}
+
 	if res.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("error response from %q: %d %s", req.URL, res.StatusCode, http.StatusText(res.StatusCode))
 	}
 
+	if c.Cache != nil {
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body for %q: %w", req.URL, err)
+		}
+
+		entry := CacheEntry{
+			Body:         body,
+			ETag:         res.Header.Get("ETag"),
+			LastModified: res.Header.Get("Last-Modified"),
+			Expires:      c.expiry(kind, res.Header),
+		}
+
+		if err := c.Cache.Put(req.Context(), key, entry); err != nil {
+			return nil, fmt.Errorf("failed to cache response for %q: %w", req.URL, err)
+		}
+
+		res.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
 	return res, nil
 }
 
+func cachedResponse(req *http.Request, entry CacheEntry) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(http.StatusOK),
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{},
+		Body:          io.NopCloser(bytes.NewReader(entry.Body)),
+		Request:       req,
+		ContentLength: int64(len(entry.Body)),
+	}
+}
+
 func (c *Client) requestHTML(ctx context.Context, url string) (*html.Node, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -281,7 +379,7 @@ func dashToEmpty(text string) string {
 func text(n *html.Node) string {
 	sb := strings.Builder{}
 
-	traverse.Depth(n, func(n *html.Node) bool {
+	traverse.DepthFunc(n, func(n *html.Node) bool {
 		if n.Type != html.TextNode {
 			if text := strings.TrimSpace(n.Data); text != "" {
 				_, _ = sb.WriteString(text)
@@ -0,0 +1,380 @@
+package pbinfo
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+)
+
+// listingPageSize is both the number of rows pbinfo renders per listing page, and the default
+// number of edges ListProblems/Search return when First/Last aren't set.
+const listingPageSize = 20
+
+// ListOptions filters and paginates the results of ListProblems and Search.
+type ListOptions struct {
+	// Grade restricts results to problems targeted at this grade. If 0, no restriction is applied.
+	Grade int
+	// Difficulty restricts results to problems of this difficulty. If Unknown, no restriction is applied.
+	Difficulty ProblemDifficulty
+	// Publisher restricts results to problems published by this user. If empty, no restriction is applied.
+	Publisher string
+	// Source restricts results to problems with this source. If empty, no restriction is applied.
+	Source string
+	// Query is a free-text search applied to the problem name. Search sets this itself; set it
+	// directly on ListProblems to reproduce a search without going through Search.
+	Query string
+
+	// After returns edges that come after this cursor.
+	After string
+	// Before returns edges that come before this cursor.
+	Before string
+	// First limits the number of edges returned after After. If 0, listingPageSize is used.
+	// Ignored if Before or Last is set.
+	First int
+	// Last limits the number of edges returned before Before. If 0, listingPageSize is used.
+	Last int
+}
+
+// ProblemEdge pairs a Problem with the opaque cursor that identifies its position in a listing.
+type ProblemEdge struct {
+	Cursor string
+	Node   *Problem
+}
+
+// PageInfo describes a ProblemPage's position within the full, unpaginated result set, following
+// the Relay cursor connection conventions.
+type PageInfo struct {
+	HasNextPage     bool
+	HasPreviousPage bool
+	StartCursor     string
+	EndCursor       string
+}
+
+// ProblemPage is a single page of a cursor-paginated problem listing, as returned by
+// ListProblems and Search.
+type ProblemPage struct {
+	Edges    []ProblemEdge
+	PageInfo PageInfo
+}
+
+// encodeCursor opaquely encodes the pbinfo listing page a problem was found on, and its offset
+// within that page's rows, into a single cursor string.
+func encodeCursor(page, offset int) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", page, offset)))
+}
+
+// decodeCursor reverses encodeCursor.
+func decodeCursor(cursor string) (page, offset int, err error) {
+	data, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid cursor %q: %w", cursor, err)
+	}
+
+	pageStr, offsetStr, ok := strings.Cut(string(data), ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid cursor %q: malformed", cursor)
+	}
+
+	page, err = strconv.Atoi(pageStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid cursor %q: %w", cursor, err)
+	}
+
+	offset, err = strconv.Atoi(offsetStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid cursor %q: %w", cursor, err)
+	}
+
+	return page, offset, nil
+}
+
+func buildListQuery(page int, opts ListOptions) url.Values {
+	q := url.Values{}
+	q.Set("pagina", strconv.Itoa(page))
+
+	if opts.Grade > 0 {
+		q.Set("clasa", strconv.Itoa(opts.Grade))
+	}
+	if opts.Difficulty != Unknown {
+		q.Set("dificultate", opts.Difficulty.String())
+	}
+	if opts.Publisher != "" {
+		q.Set("autor", opts.Publisher)
+	}
+	if opts.Source != "" {
+		q.Set("sursa", opts.Source)
+	}
+	if opts.Query != "" {
+		q.Set("cautare", opts.Query)
+	}
+
+	return q
+}
+
+var (
+	selectorProblemsListRows       = cascadia.MustCompile(`table.table-problems > tbody > tr`)
+	selectorProblemsListName       = cascadia.MustCompile(`td:nth-of-type(1) a`)
+	selectorProblemsListGrade      = cascadia.MustCompile(`td:nth-of-type(2)`)
+	selectorProblemsListDifficulty = cascadia.MustCompile(`td:nth-of-type(3)`)
+	selectorProblemsListPublisher  = cascadia.MustCompile(`td:nth-of-type(4)`)
+	selectorProblemsListSource     = cascadia.MustCompile(`td:nth-of-type(5)`)
+)
+
+// scrapeProblemsPage fetches and parses a single, server-side page of the problem listing (or
+// search results, if opts.Query is set). The returned slice has fewer than listingPageSize
+// entries only on the last page of results.
+func (c *Client) scrapeProblemsPage(ctx context.Context, page int, opts ListOptions) ([]*Problem, error) {
+	endpoint := baseEndpoint + "/probleme"
+	if opts.Query != "" {
+		endpoint = baseEndpoint + "/cauta-probleme"
+	}
+
+	root, err := c.requestHTML(ctx, endpoint+"?"+buildListQuery(page, opts).Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	rows := selectorProblemsListRows.MatchAll(root)
+	problems := make([]*Problem, 0, len(rows))
+
+	for _, row := range rows {
+		p, err := parseProblemsListRow(row)
+		if err != nil {
+			return nil, err
+		}
+
+		problems = append(problems, p)
+	}
+
+	return problems, nil
+}
+
+func parseProblemsListRow(row *html.Node) (*Problem, error) {
+	link := selectorProblemsListName.MatchFirst(row)
+	if link == nil {
+		return nil, fmt.Errorf("pbinfo: failed to find problem link in listing row: HTML changed?")
+	}
+
+	id, err := problemIDFromHref(childAttr(row, selectorProblemsListName, "href"))
+	if err != nil {
+		return nil, fmt.Errorf("pbinfo: failed to parse problem ID from listing row: %w", err)
+	}
+
+	grade, _ := strconv.Atoi(dashToEmpty(childText(row, selectorProblemsListGrade)))
+
+	return &Problem{
+		ID:         id,
+		Name:       text(link),
+		Grade:      grade,
+		Difficulty: ParseProblemDifficulty(dashToEmpty(childText(row, selectorProblemsListDifficulty))),
+		Publisher:  childText(row, selectorProblemsListPublisher),
+		Source:     dashToEmpty(childText(row, selectorProblemsListSource)),
+	}, nil
+}
+
+func problemIDFromHref(href string) (int, error) {
+	idx := strings.LastIndex(href, "/")
+	if idx == -1 || idx == len(href)-1 {
+		return 0, fmt.Errorf("unexpected href %q", href)
+	}
+
+	return strconv.Atoi(href[idx+1:])
+}
+
+// ListProblems scrapes the pbinfo problem listing, applying the given filters and returning a
+// single cursor-paginated page of results modelled after Relay connections.
+func (c *Client) ListProblems(ctx context.Context, opts ListOptions) (*ProblemPage, error) {
+	var (
+		page *ProblemPage
+		err  error
+	)
+
+	if opts.Before != "" || opts.Last > 0 {
+		page, err = c.listProblemsBackward(ctx, opts)
+	} else {
+		page, err = c.listProblemsForward(ctx, opts)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("pbinfo: %w", err)
+	}
+
+	return page, nil
+}
+
+func (c *Client) listProblemsForward(ctx context.Context, opts ListOptions) (*ProblemPage, error) {
+	page, offset := 1, 0
+
+	if opts.After != "" {
+		p, o, err := decodeCursor(opts.After)
+		if err != nil {
+			return nil, err
+		}
+
+		page, offset = p, o+1
+	}
+
+	limit := opts.First
+	if limit <= 0 {
+		limit = listingPageSize
+	}
+
+	result := &ProblemPage{PageInfo: PageInfo{HasPreviousPage: page > 1 || offset > 0}}
+
+	for {
+		rows, err := c.scrapeProblemsPage(ctx, page, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for ; offset < len(rows) && len(result.Edges) < limit; offset++ {
+			result.Edges = append(result.Edges, ProblemEdge{
+				Cursor: encodeCursor(page, offset),
+				Node:   rows[offset],
+			})
+		}
+
+		// Check the limit before the short-page rule: a page can be both short (pbinfo's last)
+		// and the one that filled limit, and rows past offset can still be unconsumed in that
+		// case, so the limit — not running out of pages — is what actually stopped us. There's
+		// more either when this page itself has leftover rows, or when it was full, in which
+		// case further pages may still follow.
+		if len(result.Edges) >= limit {
+			result.PageInfo.HasNextPage = offset < len(rows) || len(rows) >= listingPageSize
+			break
+		}
+		if len(rows) < listingPageSize {
+			break
+		}
+
+		page, offset = page+1, 0
+	}
+
+	if len(result.Edges) > 0 {
+		result.PageInfo.StartCursor = result.Edges[0].Cursor
+		result.PageInfo.EndCursor = result.Edges[len(result.Edges)-1].Cursor
+	}
+
+	return result, nil
+}
+
+// listProblemsBackward walks the listing from its start, keeping a sliding window of at most
+// limit edges immediately before opts.Before (or before the end of the results, if opts.Before
+// is empty). pbinfo's listing pages don't expose a total count, so this is the only way to
+// answer a "last N" query without scraping every page up front.
+func (c *Client) listProblemsBackward(ctx context.Context, opts ListOptions) (*ProblemPage, error) {
+	limit := opts.Last
+	if limit <= 0 {
+		limit = listingPageSize
+	}
+
+	beforePage, beforeOffset := -1, -1
+	if opts.Before != "" {
+		p, o, err := decodeCursor(opts.Before)
+		if err != nil {
+			return nil, err
+		}
+
+		beforePage, beforeOffset = p, o
+	}
+
+	type seen struct {
+		cursor  string
+		problem *Problem
+	}
+
+	var (
+		window        []seen
+		hasMoreBefore bool
+	)
+
+	for page := 1; ; page++ {
+		rows, err := c.scrapeProblemsPage(ctx, page, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for offset, p := range rows {
+			if page == beforePage && offset >= beforeOffset {
+				break
+			}
+
+			window = append(window, seen{encodeCursor(page, offset), p})
+			if len(window) > limit {
+				hasMoreBefore = true
+				window = window[1:]
+			}
+		}
+
+		if len(rows) < listingPageSize || page == beforePage {
+			break
+		}
+	}
+
+	result := &ProblemPage{
+		Edges: make([]ProblemEdge, len(window)),
+		PageInfo: PageInfo{
+			HasPreviousPage: hasMoreBefore,
+			HasNextPage:     opts.Before != "",
+		},
+	}
+
+	for i, s := range window {
+		result.Edges[i] = ProblemEdge{Cursor: s.cursor, Node: s.problem}
+	}
+
+	if len(result.Edges) > 0 {
+		result.PageInfo.StartCursor = result.Edges[0].Cursor
+		result.PageInfo.EndCursor = result.Edges[len(result.Edges)-1].Cursor
+	}
+
+	return result, nil
+}
+
+// Search looks up problems whose name matches query, scraping pbinfo's search endpoint. It
+// accepts the same filters and cursor pagination as ListProblems.
+func (c *Client) Search(ctx context.Context, query string, opts ListOptions) (*ProblemPage, error) {
+	opts.Query = query
+	return c.ListProblems(ctx, opts)
+}
+
+// ProblemSeq has the same shape as iter.Seq2[*Problem, error] (a func(yield func(*Problem, error)
+// bool)), so it can be ranged over directly on Go 1.23+, once this module's language version
+// allows it.
+type ProblemSeq func(yield func(*Problem, error) bool)
+
+// IterateProblems streams every problem matching opts, fetching additional pages on demand as
+// the sequence is consumed, so callers don't need to manage cursors themselves. Iteration stops
+// after the first error, which is yielded alongside a nil problem.
+func (c *Client) IterateProblems(ctx context.Context, opts ListOptions) ProblemSeq {
+	return func(yield func(*Problem, error) bool) {
+		opts := opts
+		opts.Before, opts.Last = "", 0
+
+		for {
+			page, err := c.ListProblems(ctx, opts)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, edge := range page.Edges {
+				if !yield(edge.Node, nil) {
+					return
+				}
+			}
+
+			if !page.PageInfo.HasNextPage {
+				return
+			}
+
+			opts.After = page.PageInfo.EndCursor
+		}
+	}
+}
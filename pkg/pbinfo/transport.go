@@ -0,0 +1,149 @@
+package pbinfo
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// defaultMaxConcurrentPerHost and defaultQPS bound how hard Client hits a single host when
+// RateLimit is left at its zero value.
+const (
+	defaultMaxConcurrentPerHost = 4
+	defaultQPS                  = 2.0
+)
+
+// RateLimit bounds how hard Client's transport is allowed to hit a single host: at most
+// MaxConcurrent requests to that host in flight at once, and at most QPS new requests to it
+// started per second. This exists because getProblemFullTestCases fans out one goroutine per
+// test case, and IterateProblems can walk arbitrarily many listing pages; without it, either
+// could hammer pbinfo with bursts of concurrent requests.
+type RateLimit struct {
+	MaxConcurrent int
+	QPS           float64
+}
+
+func (r RateLimit) withDefaults() RateLimit {
+	if r.MaxConcurrent <= 0 {
+		r.MaxConcurrent = defaultMaxConcurrentPerHost
+	}
+	if r.QPS <= 0 {
+		r.QPS = defaultQPS
+	}
+	return r
+}
+
+// hostLimiter bounds one host's traffic: sem caps concurrent in-flight requests, and bucket
+// paces how often a new one may start.
+type hostLimiter struct {
+	sem    *semaphore.Weighted
+	bucket *tokenBucket
+}
+
+func newHostLimiter(rl RateLimit) *hostLimiter {
+	rl = rl.withDefaults()
+	return &hostLimiter{
+		sem:    semaphore.NewWeighted(int64(rl.MaxConcurrent)),
+		bucket: newTokenBucket(rl.QPS),
+	}
+}
+
+func (h *hostLimiter) acquire(ctx context.Context) (release func(), err error) {
+	if err := h.sem.Acquire(ctx, 1); err != nil {
+		return nil, err
+	}
+
+	if err := h.bucket.wait(ctx); err != nil {
+		h.sem.Release(1)
+		return nil, err
+	}
+
+	released := false
+	return func() {
+		if !released {
+			released = true
+			h.sem.Release(1)
+		}
+	}, nil
+}
+
+// tokenBucket is a minimal single-token-burst rate limiter: it lets one request through
+// immediately, then paces the rest at qps requests per second.
+type tokenBucket struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func newTokenBucket(qps float64) *tokenBucket {
+	return &tokenBucket{interval: time.Duration(float64(time.Second) / qps)}
+}
+
+func (t *tokenBucket) wait(ctx context.Context) error {
+	t.mu.Lock()
+	now := time.Now()
+	wait := t.next.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	t.next = now.Add(wait + t.interval)
+	t.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// rateLimitedTransport wraps an http.RoundTripper with a per-host RateLimit, keeping a separate
+// semaphore and token bucket for every host Client talks to.
+type rateLimitedTransport struct {
+	next      http.RoundTripper
+	rateLimit RateLimit
+
+	mu    sync.Mutex
+	hosts map[string]*hostLimiter
+}
+
+func newRateLimitedTransport(next http.RoundTripper, rl RateLimit) *rateLimitedTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &rateLimitedTransport{next: next, rateLimit: rl, hosts: make(map[string]*hostLimiter)}
+}
+
+func (t *rateLimitedTransport) limiterFor(host string) *hostLimiter {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	l, ok := t.hosts[host]
+	if !ok {
+		l = newHostLimiter(t.rateLimit)
+		t.hosts[host] = l
+	}
+
+	return l
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	release, err := t.limiterFor(req.URL.Host).acquire(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,30 @@
+/*
+Package clang provides a compiler and debugger implementation that uses the
+installed LLVM/Clang toolchain on the host system.
+
+It registers the clang++ compiler and the lldb debugger.
+*/
+package clang
+
+import (
+	"os/exec"
+
+	"github.com/tmaxmax/bmoisil/pkg/toolchain"
+)
+
+const (
+	compilerName      = "clang++"
+	debuggerName      = "lldb"
+	memoryCheckerName = "valgrind"
+)
+
+var execCommandContext = exec.CommandContext
+
+func init() {
+	toolchain.RegisterCompiler(compilerName, func(pathOrExecutableName string) (toolchain.Compiler, error) {
+		return NewCompiler(pathOrExecutableName)
+	})
+	toolchain.RegisterDebugger(debuggerName, func(pathOrExecutableName string) (toolchain.Debugger, error) {
+		return NewDebugger(pathOrExecutableName)
+	})
+}
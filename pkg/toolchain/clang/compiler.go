@@ -0,0 +1,108 @@
+package clang
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/tmaxmax/bmoisil/pkg/toolchain"
+	"github.com/tmaxmax/bmoisil/pkg/toolchain/internal/gnuflags"
+)
+
+var standardsRepresentation = map[toolchain.CompileLanguageStandard]string{
+	toolchain.CompileLanguageStandardC90:   "c90",
+	toolchain.CompileLanguageStandardC99:   "c99",
+	toolchain.CompileLanguageStandardC11:   "c11",
+	toolchain.CompileLanguageStandardC17:   "c17",
+	toolchain.CompileLanguageStandardC23:   "c23",
+	toolchain.CompileLanguageStandardCPP98: "c++98",
+	toolchain.CompileLanguageStandardCPP03: "c++03",
+	toolchain.CompileLanguageStandardCPP11: "c++11",
+	toolchain.CompileLanguageStandardCPP14: "c++14",
+	toolchain.CompileLanguageStandardCPP17: "c++17",
+	toolchain.CompileLanguageStandardCPP20: "c++20",
+	toolchain.CompileLanguageStandardCPP23: "c++23",
+	toolchain.CompileLanguageStandardCPP26: "c++26",
+}
+
+// flagDialect describes how clang++ renders CompileOptions into command-line arguments.
+// It is identical to gcc's dialect, since Clang's driver is GCC-flag-compatible.
+var flagDialect = toolchain.FlagDialect{
+	Prefix: "-",
+	JoinStyles: map[string]toolchain.JoinStyle{
+		"O":                toolchain.JoinConcat,
+		"D":                toolchain.JoinConcat,
+		"L":                toolchain.JoinConcat,
+		"l":                toolchain.JoinConcat,
+		"I":                toolchain.JoinConcat,
+		"std":              toolchain.JoinEquals,
+		"fsanitize":        toolchain.JoinEquals,
+		"mindirect-branch": toolchain.JoinEquals,
+	},
+}
+
+// dialect bundles the pieces of clang++'s command-line flags that differ from g++'s, so the
+// actual CompileOptions translation can live once, in gnuflags, instead of being hand-synced
+// across both packages.
+var dialect = gnuflags.Dialect{
+	Flags:     flagDialect,
+	Standards: standardsRepresentation,
+	ErrPrefix: "clang",
+}
+
+// CompileArgs translates opts into the clang++ command-line arguments that would be used
+// to produce outputPath, without invoking the compiler. It is exported so that
+// cross-backend flag translation can be tested without a clang++ executable on the host.
+func CompileArgs(outputPath string, opts *toolchain.CompileOptions) ([]string, error) {
+	return parseOptions(outputPath, opts)
+}
+
+func parseOptions(outputPath string, opts *toolchain.CompileOptions) ([]string, error) {
+	return dialect.ParseOptions(outputPath, opts)
+}
+
+type Compiler struct {
+	info toolchain.CompilerInfo
+}
+
+var _ toolchain.Compiler = (*Compiler)(nil)
+
+func NewCompiler(pathOrExec string) (*Compiler, error) {
+	cmd := execCommandContext(context.Background(), pathOrExec, "-dumpversion")
+	version, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("clang: failed to initialize compiler: %w", err)
+	}
+
+	info := toolchain.CompilerInfo{
+		Name:                     compilerName,
+		Path:                     cmd.Path,
+		Version:                  string(bytes.TrimSpace(version)),
+		RecommendedDebugger:      debuggerName,
+		RecommendedMemoryChecker: memoryCheckerName,
+	}
+
+	return &Compiler{info: info}, nil
+}
+
+func (c *Compiler) Compile(ctx context.Context, input io.Reader, outputPath string, opts *toolchain.CompileOptions) error {
+	options, err := parseOptions(outputPath, opts)
+	if err != nil {
+		return fmt.Errorf("clang: %w", err)
+	}
+
+	args := append(options, "-")
+	cmd := execCommandContext(ctx, c.info.Path, args...)
+	cmd.Stdin = input
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("clang: failed to compile: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Compiler) Info() toolchain.CompilerInfo {
+	return c.info
+}
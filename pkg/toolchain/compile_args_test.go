@@ -0,0 +1,155 @@
+package toolchain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tmaxmax/bmoisil/pkg/toolchain"
+	"github.com/tmaxmax/bmoisil/pkg/toolchain/clang"
+	"github.com/tmaxmax/bmoisil/pkg/toolchain/gcc"
+	"github.com/tmaxmax/bmoisil/pkg/toolchain/msvc"
+)
+
+// compileArgs is implemented by both gcc.CompileArgs and clang.CompileArgs, which are
+// otherwise free functions and not tied to an interface.
+type compileArgs func(outputPath string, opts *toolchain.CompileOptions) ([]string, error)
+
+func TestCompileArgs(t *testing.T) {
+	backends := map[string]compileArgs{
+		"gcc":   gcc.CompileArgs,
+		"clang": clang.CompileArgs,
+	}
+
+	standards := []toolchain.CompileLanguageStandard{
+		toolchain.CompileLanguageStandardDefault,
+		toolchain.CompileLanguageStandardC90,
+		toolchain.CompileLanguageStandardC99,
+		toolchain.CompileLanguageStandardC11,
+		toolchain.CompileLanguageStandardC17,
+		toolchain.CompileLanguageStandardCPP98,
+		toolchain.CompileLanguageStandardCPP03,
+		toolchain.CompileLanguageStandardCPP11,
+		toolchain.CompileLanguageStandardCPP14,
+		toolchain.CompileLanguageStandardCPP17,
+		toolchain.CompileLanguageStandardCPP20,
+		toolchain.CompileLanguageStandardCPP23,
+		toolchain.CompileLanguageStandardCPP26,
+		toolchain.CompileLanguageStandardC23,
+	}
+
+	optimizations := []toolchain.CompileOptimizationLevel{
+		toolchain.CompileOptimizationNone,
+		toolchain.CompileOptimizationModerate,
+		toolchain.CompileOptimizationAggressive,
+		toolchain.CompileOptimizationDebug,
+	}
+
+	for name, compileArgs := range backends {
+		compileArgs := compileArgs
+
+		t.Run(name, func(t *testing.T) {
+			for _, standard := range standards {
+				for _, optimization := range optimizations {
+					opts := &toolchain.CompileOptions{
+						IncludePaths:      []string{"include", "vendor/include"},
+						LibraryPaths:      []string{"lib"},
+						Libraries:         []string{"m", "pthread"},
+						Defines:           []string{"DEBUG", "VERSION=2"},
+						LanguageStandard:  standard,
+						OptimizationLevel: optimization,
+						SourceFileKind:    toolchain.SourceFileKindC,
+					}
+
+					args, err := compileArgs("out", opts)
+					assert.NoError(t, err)
+					assert.Subset(t, args, []string{"-o", "out"})
+					assert.Contains(t, args, "-Iinclude")
+					assert.Contains(t, args, "-Ivendor/include")
+					assert.Contains(t, args, "-Llib")
+					assert.Contains(t, args, "-lm")
+					assert.Contains(t, args, "-lpthread")
+					assert.Contains(t, args, "-DDEBUG")
+					assert.Contains(t, args, "-DVERSION=2")
+					assert.Subset(t, args, []string{"-x", "c"})
+				}
+			}
+		})
+	}
+}
+
+func TestCompileArgsMSVC(t *testing.T) {
+	standards := []toolchain.CompileLanguageStandard{
+		toolchain.CompileLanguageStandardDefault,
+		toolchain.CompileLanguageStandardC11,
+		toolchain.CompileLanguageStandardC17,
+		toolchain.CompileLanguageStandardCPP14,
+		toolchain.CompileLanguageStandardCPP17,
+		toolchain.CompileLanguageStandardCPP20,
+		toolchain.CompileLanguageStandardCPP23,
+	}
+
+	for _, standard := range standards {
+		opts := &toolchain.CompileOptions{
+			IncludePaths:     []string{"include"},
+			LibraryPaths:     []string{"lib"},
+			Libraries:        []string{"kernel32", "user32.lib"},
+			Defines:          []string{"DEBUG"},
+			LanguageStandard: standard,
+			SourceFileKind:   toolchain.SourceFileKindC,
+		}
+
+		args, err := msvc.CompileArgs("out.exe", opts)
+		assert.NoError(t, err)
+		assert.Contains(t, args, "/Fe:out.exe")
+		assert.Contains(t, args, "/Iinclude")
+		assert.Contains(t, args, "/DDEBUG")
+		assert.Contains(t, args, "/TC")
+		assert.Contains(t, args, "/LIBPATH:lib")
+		assert.Contains(t, args, "kernel32.lib")
+		assert.Contains(t, args, "user32.lib")
+	}
+}
+
+func TestCompileArgsMSVCUnsupportedStandard(t *testing.T) {
+	_, err := msvc.CompileArgs("out.exe", &toolchain.CompileOptions{
+		LanguageStandard: toolchain.CompileLanguageStandardCPP98,
+	})
+	assert.Error(t, err)
+}
+
+func TestCompileArgsWarnings(t *testing.T) {
+	backends := map[string]compileArgs{
+		"gcc":   gcc.CompileArgs,
+		"clang": clang.CompileArgs,
+	}
+
+	for name, compileArgs := range backends {
+		t.Run(name, func(t *testing.T) {
+			args, err := compileArgs("out", &toolchain.CompileOptions{
+				Warnings: toolchain.WarningsAll | toolchain.WarningsExtra | toolchain.WarningsPedantic | toolchain.WarningsAsErrors,
+			})
+			assert.NoError(t, err)
+			assert.Contains(t, args, "-Wall")
+			assert.Contains(t, args, "-Wextra")
+			assert.Contains(t, args, "-Wpedantic")
+			assert.Contains(t, args, "-Werror")
+		})
+	}
+}
+
+func TestCompileArgsExclusiveSanitizers(t *testing.T) {
+	backends := map[string]compileArgs{
+		"gcc":   gcc.CompileArgs,
+		"clang": clang.CompileArgs,
+	}
+
+	for name, compileArgs := range backends {
+		t.Run(name, func(t *testing.T) {
+			_, err := compileArgs("out", &toolchain.CompileOptions{
+				Sanitizers: []toolchain.Sanitizer{toolchain.AddressSanitizer, toolchain.ThreadSanitizer},
+			})
+			assert.Error(t, err)
+		})
+	}
+}
@@ -13,13 +13,26 @@ import (
 type Compiler interface {
 	// Compile compiles the given input using a C++ compiler to an executable.
 	// It parses the given options to the format required by the underlying compiler
-	// and then outputs the compiled source file as an executable,
-	// which is written to the provided writer. The compile options may be nil.
-	Compile(ctx context.Context, input io.Reader, output io.Writer, options *CompileOptions) error
+	// and then writes the compiled executable at outputPath. The compile options may be nil.
+	//
+	// outputPath, not an io.Writer, is required because every underlying compiler driver
+	// (gcc, clang, msvc) takes an output path on its own command line rather than writing to
+	// an arbitrary stream.
+	Compile(ctx context.Context, input io.Reader, outputPath string, options *CompileOptions) error
 	// Info returns some information about the compiler.
 	Info() CompilerInfo
 }
 
+// SourceFileKind indicates whether a source file is written in C or in C++.
+type SourceFileKind int
+
+const (
+	// SourceFileKindCPP indicates the source file is written in C++. This is the default.
+	SourceFileKindCPP SourceFileKind = iota
+	// SourceFileKindC indicates the source file is written in C.
+	SourceFileKindC
+)
+
 // CompileOptions customizes the compilation process in a compiler-agnostic way.
 // Each option is translated to the compiler-specific flags.
 type CompileOptions struct {
@@ -39,6 +52,24 @@ type CompileOptions struct {
 	Defines []string
 	// Undefs specifies a list of macros that should be undefined.
 	Undefs []string
+	// Sanitizers enables runtime instrumentation that detects classes of bugs.
+	// Not every combination of sanitizers can be used together; Compile returns
+	// an error if the underlying compiler cannot honor the requested combination.
+	Sanitizers []Sanitizer
+	// StackProtector selects how aggressively the compiler inserts stack-smashing
+	// protection into the generated code.
+	StackProtector StackProtectorLevel
+	// SpectreMitigation enables compiler mitigations against Spectre-class
+	// speculative execution vulnerabilities.
+	SpectreMitigation bool
+	// FortifySource selects the level of _FORTIFY_SOURCE hardening applied to
+	// calls to known-unsafe libc functions. 0 disables it.
+	FortifySource int
+	// SourceFileKind indicates whether the input is C or C++. Defaults to C++.
+	SourceFileKind SourceFileKind
+	// Warnings selects which classes of compiler warnings are enabled. Its values can be
+	// combined with a bitwise or, e.g. WarningsAll|WarningsExtra.
+	Warnings Warnings
 	// Flags can be used to specifiy other compiler options that are not available
 	// in CompileOptions. These flags are not translated, so compilers may not be
 	// able to be used interchangeably when this option is used. They also override
@@ -46,6 +77,54 @@ type CompileOptions struct {
 	Flags Flags
 }
 
+// Warnings values select which classes of compiler warnings are enabled. They can be
+// combined with a bitwise or.
+type Warnings int
+
+const (
+	// WarningsAll enables the compiler's base set of commonly-useful warnings.
+	WarningsAll Warnings = 1 << iota
+	// WarningsExtra enables additional warnings beyond WarningsAll.
+	WarningsExtra
+	// WarningsPedantic warns about any use of non-standard language extensions.
+	WarningsPedantic
+	// WarningsAsErrors turns every enabled warning into an error.
+	WarningsAsErrors
+)
+
+// Sanitizer values enable a compiler's runtime instrumentation that detects a
+// particular class of bugs.
+type Sanitizer int
+
+const (
+	// AddressSanitizer detects out-of-bounds accesses and use-after-free bugs.
+	AddressSanitizer Sanitizer = iota
+	// UndefinedBehaviorSanitizer detects undefined behavior, such as signed integer overflow.
+	UndefinedBehaviorSanitizer
+	// ThreadSanitizer detects data races. It cannot be combined with AddressSanitizer or MemorySanitizer.
+	ThreadSanitizer
+	// MemorySanitizer detects reads of uninitialized memory. It cannot be combined with
+	// AddressSanitizer or ThreadSanitizer.
+	MemorySanitizer
+	// LeakSanitizer detects memory leaks. It is implied by AddressSanitizer.
+	LeakSanitizer
+	// FuzzerSanitizer instruments the program for coverage-guided fuzzing.
+	FuzzerSanitizer
+)
+
+// StackProtectorLevel values select how aggressively stack-smashing protection
+// is inserted into the generated code.
+type StackProtectorLevel int
+
+const (
+	// StackProtectorNone disables stack-smashing protection.
+	StackProtectorNone StackProtectorLevel = iota
+	// StackProtectorStrong protects functions that use arrays or take the address of a local variable.
+	StackProtectorStrong
+	// StackProtectorAll protects every function, at a greater performance cost.
+	StackProtectorAll
+)
+
 // CompileOptimizationLevel values are used to specify the optimization level used by the compiler.
 // These levels do not toggle only the optimization flags of the compiler. For convenience, they
 // might also toggle other flags - see the documentation for each value.
@@ -85,6 +164,9 @@ const (
 	CompileLanguageStandardCPP14
 	CompileLanguageStandardCPP17
 	CompileLanguageStandardCPP20
+	CompileLanguageStandardCPP23
+	CompileLanguageStandardCPP26
+	CompileLanguageStandardC23
 )
 
 // CompilerInfo holds some information about the underlying compiler.
@@ -99,6 +181,10 @@ type CompilerInfo struct {
 	// If non-empty, the name can be used to instantiate a Debugger instance, if
 	// the debugger implementation with the given name is present.
 	RecommendedDebugger string
+	// RecommendedMemoryChecker to be used with executables outputted by this compiler.
+	// If non-empty, the name can be used to instantiate a MemoryChecker instance, if
+	// the memory checker implementation with the given name is present.
+	RecommendedMemoryChecker string
 }
 
 // NewCompiler looks up the compiler's executable with the given name on the host
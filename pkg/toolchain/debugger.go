@@ -3,17 +3,52 @@ package toolchain
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"sync"
 )
 
 // A Debugger is used to debug compiled executables.
 type Debugger interface {
-	// Debug runs the debugger for the given executable.
-	Debug(ctx context.Context, executablePath string) error
+	// Debug runs the debugger for the given executable, with its standard streams wired
+	// to the given DebuggerStreams. If streams is nil, the OS standard streams are used.
+	Debug(ctx context.Context, executablePath string, streams *DebuggerStreams) error
+	// NewSession opens an interactive, programmatically driven DebugSession for the
+	// given executable.
+	NewSession(ctx context.Context, executablePath string) (DebugSession, error)
 	// Info returns some information about the debugger.
 	Info() DebuggerInfo
 }
 
+// DebuggerStreams overrides the standard streams used for an interactive Debug session.
+// Any nil field defaults to the corresponding OS standard stream.
+type DebuggerStreams struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// GetDebuggerStreams resolves the given DebuggerStreams to concrete streams, substituting
+// the OS standard streams for any that are nil, or if streams itself is nil.
+func GetDebuggerStreams(streams *DebuggerStreams) (stdin io.Reader, stdout, stderr io.Writer) {
+	stdin, stdout, stderr = os.Stdin, os.Stdout, os.Stderr
+	if streams == nil {
+		return stdin, stdout, stderr
+	}
+
+	if streams.Stdin != nil {
+		stdin = streams.Stdin
+	}
+	if streams.Stdout != nil {
+		stdout = streams.Stdout
+	}
+	if streams.Stderr != nil {
+		stderr = streams.Stderr
+	}
+
+	return stdin, stdout, stderr
+}
+
 // DebuggerInfo holds some information about the underlying debugger.
 type DebuggerInfo struct {
 	// Name of the debugger.
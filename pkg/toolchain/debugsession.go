@@ -0,0 +1,99 @@
+package toolchain
+
+import (
+	"context"
+	"io"
+)
+
+// A DebugSession is an interactive, programmatically driven debugging session opened
+// on top of a Debugger. Unlike Debug, which wires the debugger's standard streams
+// straight through for a human to drive, a DebugSession lets callers script it.
+type DebugSession interface {
+	// SetBreakpoint sets a breakpoint at the given location (e.g. a function name, or
+	// a file:line pair) and returns the breakpoint that was set.
+	SetBreakpoint(ctx context.Context, location string) (Breakpoint, error)
+	// Run starts the debugged program with the given arguments. Implementations are not
+	// required to support piping stdin to the inferior; a non-nil stdin they can't honor
+	// should be rejected with an error rather than silently ignored.
+	Run(ctx context.Context, args []string, stdin io.Reader) error
+	// Continue resumes execution until the next breakpoint or until the program exits.
+	Continue(ctx context.Context) error
+	// Step executes the next line, stepping into any function calls it makes.
+	Step(ctx context.Context) error
+	// Next executes the next line, stepping over any function calls it makes.
+	Next(ctx context.Context) error
+	// Finish runs until the current function returns.
+	Finish(ctx context.Context) error
+	// EvaluateExpression evaluates expr in the context of the current frame and
+	// returns its value as formatted by the debugger.
+	EvaluateExpression(ctx context.Context, expr string) (string, error)
+	// ReadStack returns the current call stack, innermost frame first.
+	ReadStack(ctx context.Context) ([]DebugFrame, error)
+	// ReadLocals returns the local variables (and arguments) of the current frame.
+	ReadLocals(ctx context.Context) ([]Variable, error)
+	// Events returns a channel of asynchronous notifications emitted while the program
+	// runs, such as stops and exits. The channel is closed once the session ends.
+	Events() <-chan DebugEvent
+	// Close ends the session, asking the debugger to exit and waiting for its process to finish.
+	Close() error
+}
+
+// Breakpoint describes a breakpoint set in a DebugSession.
+type Breakpoint struct {
+	// Number identifying the breakpoint, used by the underlying debugger.
+	Number int
+	// Location the breakpoint was requested at.
+	Location string
+	// File the breakpoint resolved to, if known.
+	File string
+	// Line the breakpoint resolved to within File, if known.
+	Line int
+}
+
+// DebugFrame describes a single frame of a program's call stack.
+type DebugFrame struct {
+	// Level of the frame, 0 being the innermost.
+	Level int
+	// Function the frame belongs to.
+	Function string
+	// File containing the currently executing line, if known.
+	File string
+	// Line currently executing within File, if known.
+	Line int
+	// Address is the frame's instruction pointer.
+	Address string
+}
+
+// Variable describes a single local variable or argument read from a DebugSession.
+type Variable struct {
+	Name  string
+	Value string
+	Type  string
+}
+
+// DebugEventKind identifies the kind of a DebugEvent.
+type DebugEventKind int
+
+const (
+	// DebugEventStopped is emitted when the program stops, e.g. at a breakpoint or after a step.
+	DebugEventStopped DebugEventKind = iota
+	// DebugEventExited is emitted when the debugged program terminates.
+	DebugEventExited
+	// DebugEventOutput is emitted for output produced by the debugged program or the debugger itself.
+	DebugEventOutput
+)
+
+// DebugEvent is an asynchronous notification emitted by a DebugSession while a program runs.
+type DebugEvent struct {
+	// Kind of event.
+	Kind DebugEventKind
+	// Reason the event was emitted, as reported by the debugger (e.g. "breakpoint-hit").
+	// Only set for DebugEventStopped and DebugEventExited.
+	Reason string
+	// Frame execution stopped at. Only set for DebugEventStopped.
+	Frame *DebugFrame
+	// ExitCode of the program. Only set for DebugEventExited.
+	ExitCode int
+	// Output text. Only set for DebugEventOutput.
+	Output string
+}
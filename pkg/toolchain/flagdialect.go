@@ -0,0 +1,55 @@
+package toolchain
+
+// JoinStyle controls how a flag name and its value are joined into a command-line argument.
+type JoinStyle int
+
+const (
+	// JoinSpace renders the flag and its value as two separate arguments, e.g. "-I", "path".
+	// This is the default style for a flag with no entry in FlagDialect.JoinStyles.
+	JoinSpace JoinStyle = iota
+	// JoinConcat renders the flag and its value concatenated into a single argument,
+	// e.g. "-Ipath".
+	JoinConcat
+	// JoinEquals renders the flag and its value joined by "=", e.g. "-std=c++20".
+	JoinEquals
+	// JoinColon renders the flag and its value joined by ":", e.g. "/std:c++20".
+	JoinColon
+)
+
+// A FlagDialect describes how a compiler renders a set of Flags into command-line
+// arguments. Every compiler package parameterizes one to reuse the shared rendering
+// logic in Render, instead of reimplementing its own flag-formatting switch.
+type FlagDialect struct {
+	// Prefix is prepended to every flag name, e.g. "-" for GCC/Clang or "/" for MSVC.
+	Prefix string
+	// JoinStyles selects the JoinStyle used for specific flag names. Flags not present
+	// here are rendered with JoinSpace.
+	JoinStyles map[string]JoinStyle
+}
+
+// Render translates flags into command-line arguments according to the dialect.
+func (d FlagDialect) Render(flags Flags) []string {
+	var out []string
+
+	flags.Range(func(flag string, values []string, isToggle bool) {
+		if isToggle {
+			out = append(out, d.Prefix+flag)
+			return
+		}
+
+		for _, value := range values {
+			switch d.JoinStyles[flag] {
+			case JoinConcat:
+				out = append(out, d.Prefix+flag+value)
+			case JoinEquals:
+				out = append(out, d.Prefix+flag+"="+value)
+			case JoinColon:
+				out = append(out, d.Prefix+flag+":"+value)
+			default:
+				out = append(out, d.Prefix+flag, value)
+			}
+		}
+	})
+
+	return out
+}
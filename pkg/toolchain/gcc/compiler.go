@@ -7,6 +7,7 @@ import (
 	"io"
 
 	"github.com/tmaxmax/bmoisil/pkg/toolchain"
+	"github.com/tmaxmax/bmoisil/pkg/toolchain/internal/gnuflags"
 )
 
 var standardsRepresentation = map[toolchain.CompileLanguageStandard]string{
@@ -14,93 +15,50 @@ var standardsRepresentation = map[toolchain.CompileLanguageStandard]string{
 	toolchain.CompileLanguageStandardC99:   "c99",
 	toolchain.CompileLanguageStandardC11:   "c11",
 	toolchain.CompileLanguageStandardC17:   "c17",
+	toolchain.CompileLanguageStandardC23:   "c23",
 	toolchain.CompileLanguageStandardCPP98: "c++98",
 	toolchain.CompileLanguageStandardCPP03: "c++03",
 	toolchain.CompileLanguageStandardCPP11: "c++11",
 	toolchain.CompileLanguageStandardCPP14: "c++14",
 	toolchain.CompileLanguageStandardCPP17: "c++17",
 	toolchain.CompileLanguageStandardCPP20: "c++20",
+	toolchain.CompileLanguageStandardCPP23: "c++23",
+	toolchain.CompileLanguageStandardCPP26: "c++26",
 }
 
-func addLanguageStandardFlag(flags toolchain.Flags, standard toolchain.CompileLanguageStandard) {
-	standardRepr := standardsRepresentation[standard]
-	if standardRepr == "" {
-		return
-	}
-
-	flags.Set("std", standardRepr)
-}
-
-func addOptimizationFlags(flags toolchain.Flags, optimization toolchain.CompileOptimizationLevel) {
-	switch optimization {
-	case toolchain.CompileOptimizationNone:
-		flags.Set("O", "0")
-	case toolchain.CompileOptimizationModerate:
-		flags.Set("O", "1")
-	case toolchain.CompileOptimizationAggressive:
-		flags.Set("O", "2")
-	case toolchain.CompileOptimizationDebug:
-		flags.Set("O", "g")
-		flags.Toggle("ggdb")
-	}
+// flagDialect describes how g++ renders CompileOptions into command-line arguments.
+var flagDialect = toolchain.FlagDialect{
+	Prefix: "-",
+	JoinStyles: map[string]toolchain.JoinStyle{
+		"O":                toolchain.JoinConcat,
+		"D":                toolchain.JoinConcat,
+		"L":                toolchain.JoinConcat,
+		"l":                toolchain.JoinConcat,
+		"I":                toolchain.JoinConcat,
+		"std":              toolchain.JoinEquals,
+		"fsanitize":        toolchain.JoinEquals,
+		"mindirect-branch": toolchain.JoinEquals,
+	},
 }
 
-func addSourceKindFlag(flags toolchain.Flags, kind toolchain.SourceFileKind) {
-	if kind == toolchain.SourceFileKindC {
-		flags.Set("x", "c")
-	} else {
-		flags.Set("x", "c++")
-	}
+// dialect bundles the pieces of g++'s command-line flags that differ from Clang's, so the
+// actual CompileOptions translation can live once, in gnuflags, instead of being hand-synced
+// across both packages.
+var dialect = gnuflags.Dialect{
+	Flags:     flagDialect,
+	Standards: standardsRepresentation,
+	ErrPrefix: "gcc",
 }
 
-func parseOptions(outputPath string, opts *toolchain.CompileOptions) []string {
-	flags := make(toolchain.Flags)
-	flags.Set("o", outputPath)
-
-	if opts == nil {
-		flags.Set("x", "c++")
-		return parseFlags(flags)
-	}
-
-	flags.Set("D", opts.Defines...)
-	flags.Set("U", opts.Undefs...)
-	flags.Set("L", opts.LibraryPaths...)
-	flags.Set("l", opts.Libraries...)
-	flags.Set("I", opts.IncludePaths...)
-	addLanguageStandardFlag(flags, opts.LanguageStandard)
-	addOptimizationFlags(flags, opts.OptimizationLevel)
-	addSourceKindFlag(flags, opts.SourceFileKind)
-	flags.Merge(opts.Flags)
-
-	return parseFlags(flags)
+// CompileArgs translates opts into the g++ command-line arguments that would be used to
+// produce outputPath, without invoking the compiler. It is exported so that cross-backend
+// flag translation can be tested without a g++ executable on the host.
+func CompileArgs(outputPath string, opts *toolchain.CompileOptions) ([]string, error) {
+	return parseOptions(outputPath, opts)
 }
 
-func parseFlags(flags toolchain.Flags) []string {
-	const flagStart = "-"
-	var out []string
-
-	flags.Range(func(flag string, values []string, isToggle bool) {
-		if isToggle {
-			out = append(out, flagStart+flag)
-			return
-		}
-
-		// TODO: quote value if necessary?
-
-		for _, value := range values {
-			switch flag {
-			case "O", "D", "L", "l", "I":
-				out = append(out, flagStart+flag+value)
-			case "std":
-				out = append(out, flagStart+flag+"="+value)
-			default:
-				out = append(out, flagStart+flag)
-				out = append(out, value)
-			}
-		}
-	})
-
-	return out
+func parseOptions(outputPath string, opts *toolchain.CompileOptions) ([]string, error) {
+	return dialect.ParseOptions(outputPath, opts)
 }
 
 type Compiler struct {
@@ -117,17 +75,23 @@ func NewCompiler(pathOrExec string) (*Compiler, error) {
 	}
 
 	info := toolchain.CompilerInfo{
-		Name:                compilerName,
-		Path:                cmd.Path,
-		Version:             string(bytes.TrimSpace(version)),
-		RecommendedDebugger: debuggerName,
+		Name:                     compilerName,
+		Path:                     cmd.Path,
+		Version:                  string(bytes.TrimSpace(version)),
+		RecommendedDebugger:      debuggerName,
+		RecommendedMemoryChecker: memoryCheckerName,
 	}
 
 	return &Compiler{info: info}, nil
 }
 
 func (c *Compiler) Compile(ctx context.Context, input io.Reader, outputPath string, opts *toolchain.CompileOptions) error {
-	args := append(parseOptions(outputPath, opts), "-")
+	options, err := parseOptions(outputPath, opts)
+	if err != nil {
+		return fmt.Errorf("gcc: %w", err)
+	}
+
+	args := append(options, "-")
 	cmd := execCommandContext(ctx, c.info.Path, args...)
 	cmd.Stdin = input
 
@@ -55,3 +55,8 @@ func (d *Debugger) Debug(ctx context.Context, executablePath string, streams *to
 func (d *Debugger) Info() toolchain.DebuggerInfo {
 	return d.info
 }
+
+// NewSession opens an interactive GDB/MI session for the given executable.
+func (d *Debugger) NewSession(ctx context.Context, executablePath string) (toolchain.DebugSession, error) {
+	return newSession(ctx, d.info.Path, executablePath)
+}
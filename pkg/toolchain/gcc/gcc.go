@@ -13,8 +13,9 @@ import (
 )
 
 const (
-	compilerName = "g++"
-	debuggerName = "gdb"
+	compilerName      = "g++"
+	debuggerName      = "gdb"
+	memoryCheckerName = "valgrind"
 )
 
 var execCommandContext = exec.CommandContext
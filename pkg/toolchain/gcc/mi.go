@@ -0,0 +1,284 @@
+package gcc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// miRecordKind identifies the kind of a single line of GDB/MI output.
+type miRecordKind int
+
+const (
+	miResult miRecordKind = iota
+	miExecAsync
+	miStatusAsync
+	miNotifyAsync
+	miConsoleStream
+	miTargetStream
+	miLogStream
+	miPrompt
+)
+
+// miRecord is a single parsed line of GDB/MI output, as produced by `gdb --interpreter=mi3`.
+type miRecord struct {
+	kind    miRecordKind
+	token   string
+	class   string
+	results map[string]any
+	text    string // set for the stream record kinds
+}
+
+// parseMILine parses a single line of GDB/MI output. It returns a nil record, with no
+// error, for blank lines.
+func parseMILine(line string) (*miRecord, error) {
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return nil, nil
+	}
+	if line == "(gdb)" {
+		return &miRecord{kind: miPrompt}, nil
+	}
+
+	p := &miParser{s: line}
+
+	tokenStart := p.i
+	for p.i < len(p.s) && isDigit(p.s[p.i]) {
+		p.i++
+	}
+	token := p.s[tokenStart:p.i]
+
+	if p.i >= len(p.s) {
+		return nil, fmt.Errorf("malformed MI record %q", line)
+	}
+
+	marker := p.s[p.i]
+	p.i++
+
+	switch marker {
+	case '~', '@', '&':
+		text, err := p.parseCString()
+		if err != nil {
+			return nil, fmt.Errorf("malformed MI stream record %q: %w", line, err)
+		}
+
+		kind := miConsoleStream
+		switch marker {
+		case '@':
+			kind = miTargetStream
+		case '&':
+			kind = miLogStream
+		}
+
+		return &miRecord{kind: kind, text: text}, nil
+	case '^', '*', '+', '=':
+		class := p.parseIdent()
+		results, err := p.parseResultList()
+		if err != nil {
+			return nil, fmt.Errorf("malformed MI record %q: %w", line, err)
+		}
+
+		kind := miResult
+		switch marker {
+		case '*':
+			kind = miExecAsync
+		case '+':
+			kind = miStatusAsync
+		case '=':
+			kind = miNotifyAsync
+		}
+
+		return &miRecord{kind: kind, token: token, class: class, results: results}, nil
+	default:
+		return nil, fmt.Errorf("unrecognized MI record prefix %q in %q", string(marker), line)
+	}
+}
+
+// miParser parses the GDB/MI value grammar: c-strings with backslash escaping, tuples
+// ("{result,...}"), and lists ("[value,...]" or "[result,...]").
+type miParser struct {
+	s string
+	i int
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdent(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '-'
+}
+
+func (p *miParser) parseIdent() string {
+	start := p.i
+	for p.i < len(p.s) && isIdent(p.s[p.i]) {
+		p.i++
+	}
+	return p.s[start:p.i]
+}
+
+// parseCString parses a double-quoted, backslash-escaped string.
+func (p *miParser) parseCString() (string, error) {
+	if p.i >= len(p.s) || p.s[p.i] != '"' {
+		return "", fmt.Errorf("expected '\"' at offset %d", p.i)
+	}
+	p.i++
+
+	var sb strings.Builder
+	for p.i < len(p.s) {
+		c := p.s[p.i]
+		switch c {
+		case '"':
+			p.i++
+			return sb.String(), nil
+		case '\\':
+			p.i++
+			if p.i >= len(p.s) {
+				return "", fmt.Errorf("unterminated escape sequence")
+			}
+			switch e := p.s[p.i]; e {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			default:
+				sb.WriteByte(e)
+			}
+			p.i++
+		default:
+			sb.WriteByte(c)
+			p.i++
+		}
+	}
+
+	return "", fmt.Errorf("unterminated c-string")
+}
+
+// parseValue parses a const (c-string), a tuple, or a list.
+func (p *miParser) parseValue() (any, error) {
+	if p.i >= len(p.s) {
+		return nil, fmt.Errorf("unexpected end of value")
+	}
+
+	switch p.s[p.i] {
+	case '"':
+		return p.parseCString()
+	case '{':
+		return p.parseTuple()
+	case '[':
+		return p.parseList()
+	default:
+		return nil, fmt.Errorf("unexpected character %q at offset %d", p.s[p.i], p.i)
+	}
+}
+
+// parseResult parses a single "variable=value" pair.
+func (p *miParser) parseResult() (string, any, error) {
+	key := p.parseIdent()
+	if key == "" {
+		return "", nil, fmt.Errorf("expected variable name at offset %d", p.i)
+	}
+	if p.i >= len(p.s) || p.s[p.i] != '=' {
+		return "", nil, fmt.Errorf("expected '=' after %q", key)
+	}
+	p.i++
+
+	value, err := p.parseValue()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return key, value, nil
+}
+
+// parseResultList parses the zero-or-more comma-prefixed results trailing an MI record's class.
+func (p *miParser) parseResultList() (map[string]any, error) {
+	results := map[string]any{}
+
+	for p.i < len(p.s) && p.s[p.i] == ',' {
+		p.i++
+
+		key, value, err := p.parseResult()
+		if err != nil {
+			return nil, err
+		}
+
+		results[key] = value
+	}
+
+	return results, nil
+}
+
+// parseTuple parses a "{result,...}" value.
+func (p *miParser) parseTuple() (map[string]any, error) {
+	p.i++ // '{'
+
+	tuple := map[string]any{}
+	if p.i < len(p.s) && p.s[p.i] == '}' {
+		p.i++
+		return tuple, nil
+	}
+
+	for {
+		key, value, err := p.parseResult()
+		if err != nil {
+			return nil, err
+		}
+		tuple[key] = value
+
+		if p.i < len(p.s) && p.s[p.i] == ',' {
+			p.i++
+			continue
+		}
+		break
+	}
+
+	if p.i >= len(p.s) || p.s[p.i] != '}' {
+		return nil, fmt.Errorf("expected '}' at offset %d", p.i)
+	}
+	p.i++
+
+	return tuple, nil
+}
+
+// parseList parses a "[value,...]" or "[result,...]" value.
+func (p *miParser) parseList() ([]any, error) {
+	p.i++ // '['
+
+	var list []any
+	if p.i < len(p.s) && p.s[p.i] == ']' {
+		p.i++
+		return list, nil
+	}
+
+	for {
+		var value any
+		var err error
+
+		if p.i < len(p.s) && isIdentStart(p.s[p.i]) {
+			_, value, err = p.parseResult()
+		} else {
+			value, err = p.parseValue()
+		}
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+
+		if p.i < len(p.s) && p.s[p.i] == ',' {
+			p.i++
+			continue
+		}
+		break
+	}
+
+	if p.i >= len(p.s) || p.s[p.i] != ']' {
+		return nil, fmt.Errorf("expected ']' at offset %d", p.i)
+	}
+	p.i++
+
+	return list, nil
+}
@@ -0,0 +1,167 @@
+package gcc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMILine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want *miRecord
+	}{
+		{
+			name: "blank line",
+			line: "",
+			want: nil,
+		},
+		{
+			name: "prompt",
+			line: "(gdb)",
+			want: &miRecord{kind: miPrompt},
+		},
+		{
+			name: "console stream",
+			line: `~"Reading symbols from a.out...\n"`,
+			want: &miRecord{kind: miConsoleStream, text: "Reading symbols from a.out...\n"},
+		},
+		{
+			name: "target stream",
+			line: `@"hello\n"`,
+			want: &miRecord{kind: miTargetStream, text: "hello\n"},
+		},
+		{
+			name: "log stream",
+			line: `&"warning: foo\n"`,
+			want: &miRecord{kind: miLogStream, text: "warning: foo\n"},
+		},
+		{
+			name: "result record with no results",
+			line: `^done`,
+			want: &miRecord{kind: miResult, class: "done", results: map[string]any{}},
+		},
+		{
+			name: "result record with a token",
+			line: `42^done`,
+			want: &miRecord{kind: miResult, token: "42", class: "done", results: map[string]any{}},
+		},
+		{
+			name: "result record with a scalar result",
+			line: `^done,reason="exited-normally"`,
+			want: &miRecord{kind: miResult, class: "done", results: map[string]any{"reason": "exited-normally"}},
+		},
+		{
+			name: "exec-async record",
+			line: `*stopped,reason="breakpoint-hit",bkptno="1"`,
+			want: &miRecord{
+				kind:  miExecAsync,
+				class: "stopped",
+				results: map[string]any{
+					"reason": "breakpoint-hit",
+					"bkptno": "1",
+				},
+			},
+		},
+		{
+			name: "status-async record",
+			line: `+download,section="text"`,
+			want: &miRecord{kind: miStatusAsync, class: "download", results: map[string]any{"section": "text"}},
+		},
+		{
+			name: "notify-async record",
+			line: `=thread-created,id="1"`,
+			want: &miRecord{kind: miNotifyAsync, class: "thread-created", results: map[string]any{"id": "1"}},
+		},
+		{
+			name: "tuple result",
+			line: `^done,frame={level="0",func="main",line="3"}`,
+			want: &miRecord{
+				kind:  miResult,
+				class: "done",
+				results: map[string]any{
+					"frame": map[string]any{"level": "0", "func": "main", "line": "3"},
+				},
+			},
+		},
+		{
+			name: "list of values",
+			line: `^done,args=["1","2"]`,
+			want: &miRecord{
+				kind:    miResult,
+				class:   "done",
+				results: map[string]any{"args": []any{"1", "2"}},
+			},
+		},
+		{
+			name: "list of results",
+			line: `^done,breakpoints=[number="1",number="2"]`,
+			want: &miRecord{
+				kind:    miResult,
+				class:   "done",
+				results: map[string]any{"breakpoints": []any{"1", "2"}},
+			},
+		},
+		{
+			name: "empty tuple and list",
+			line: `^done,a={},b=[]`,
+			want: &miRecord{
+				kind:    miResult,
+				class:   "done",
+				results: map[string]any{"a": map[string]any{}, "b": []any(nil)},
+			},
+		},
+		{
+			name: "nested tuples and lists",
+			line: `^done,frame={level="0",args=[{name="argc",value="1"}]}`,
+			want: &miRecord{
+				kind:  miResult,
+				class: "done",
+				results: map[string]any{
+					"frame": map[string]any{
+						"level": "0",
+						"args":  []any{map[string]any{"name": "argc", "value": "1"}},
+					},
+				},
+			},
+		},
+		{
+			name: "escape sequences",
+			line: `~"a\tb\nc\rd\\e\"f"`,
+			want: &miRecord{kind: miConsoleStream, text: "a\tb\nc\rd\\e\"f"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMILine(tt.line)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseMILineErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{name: "no marker", line: "42"},
+		{name: "unrecognized marker", line: `42?done`},
+		{name: "unterminated c-string", line: `~"unterminated`},
+		{name: "missing result name", line: `^done,="x"`},
+		{name: "missing equals", line: `^done,reason"x"`},
+		{name: "unterminated tuple", line: `^done,frame={level="0"`},
+		{name: "unterminated list", line: `^done,args=["1","2"`},
+		{name: "unexpected value character", line: `^done,reason=x`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseMILine(tt.line)
+			assert.Error(t, err)
+		})
+	}
+}
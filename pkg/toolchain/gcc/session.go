@@ -0,0 +1,282 @@
+package gcc
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/tmaxmax/bmoisil/pkg/toolchain"
+)
+
+// Session drives an interactive GDB session through its Machine Interface
+// (`gdb --interpreter=mi3`), letting callers script breakpoints, execution and
+// inspection instead of wiring a human to gdb's terminal.
+type Session struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+
+	token uint64
+
+	mu      sync.Mutex
+	pending map[string]chan *miRecord
+
+	events chan toolchain.DebugEvent
+	done   chan struct{}
+}
+
+var _ toolchain.DebugSession = (*Session)(nil)
+
+func newSession(ctx context.Context, gdbPath, executablePath string) (*Session, error) {
+	cmd := execCommandContext(ctx, gdbPath, "--interpreter=mi3", "--quiet", "--nx", executablePath)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("gcc: failed to open debugger stdin: %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("gcc: failed to open debugger stdout: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("gcc: failed to start debugger: %w", err)
+	}
+
+	s := &Session{
+		cmd:     cmd,
+		stdin:   stdin,
+		pending: make(map[string]chan *miRecord),
+		events:  make(chan toolchain.DebugEvent, 16),
+		done:    make(chan struct{}),
+	}
+
+	go s.readLoop(bufio.NewScanner(stdout))
+
+	return s, nil
+}
+
+func (s *Session) nextToken() string {
+	return strconv.FormatUint(atomic.AddUint64(&s.token, 1), 10)
+}
+
+// execute sends an MI command and waits for its matching result record.
+func (s *Session) execute(ctx context.Context, command string) (*miRecord, error) {
+	token := s.nextToken()
+	ch := make(chan *miRecord, 1)
+
+	s.mu.Lock()
+	s.pending[token] = ch
+	s.mu.Unlock()
+
+	if _, err := io.WriteString(s.stdin, token+command+"\n"); err != nil {
+		return nil, fmt.Errorf("gcc: failed to write MI command: %w", err)
+	}
+
+	select {
+	case rec := <-ch:
+		if rec.class == "error" {
+			return rec, fmt.Errorf("gcc: %v", rec.results["msg"])
+		}
+		return rec, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-s.done:
+		return nil, fmt.Errorf("gcc: debug session has ended")
+	}
+}
+
+func (s *Session) readLoop(scanner *bufio.Scanner) {
+	defer close(s.done)
+	defer close(s.events)
+
+	for scanner.Scan() {
+		rec, err := parseMILine(scanner.Text())
+		if err != nil || rec == nil {
+			continue
+		}
+
+		switch rec.kind {
+		case miResult:
+			s.mu.Lock()
+			ch := s.pending[rec.token]
+			delete(s.pending, rec.token)
+			s.mu.Unlock()
+
+			if ch != nil {
+				ch <- rec
+			}
+		case miExecAsync:
+			s.dispatchExecAsync(rec)
+		case miConsoleStream, miTargetStream:
+			s.events <- toolchain.DebugEvent{Kind: toolchain.DebugEventOutput, Output: rec.text}
+		}
+	}
+}
+
+func (s *Session) dispatchExecAsync(rec *miRecord) {
+	reason, _ := rec.results["reason"].(string)
+
+	if strings.HasPrefix(reason, "exited") {
+		code := 0
+		if raw, ok := rec.results["exit-code"].(string); ok {
+			// GDB/MI formats this field as "0%o": a literal leading zero plus the code in
+			// octal, not decimal (e.g. exit code 8 is reported as "010").
+			if v, err := strconv.ParseInt(strings.TrimPrefix(raw, "0"), 8, 64); err == nil {
+				code = int(v)
+			}
+		}
+		s.events <- toolchain.DebugEvent{Kind: toolchain.DebugEventExited, Reason: reason, ExitCode: code}
+		return
+	}
+
+	if rec.class == "stopped" {
+		s.events <- toolchain.DebugEvent{Kind: toolchain.DebugEventStopped, Reason: reason, Frame: parseFrame(rec.results["frame"])}
+	}
+}
+
+func parseFrame(v any) *toolchain.DebugFrame {
+	tuple, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	frame := &toolchain.DebugFrame{
+		Function: asString(tuple["func"]),
+		File:     asString(tuple["file"]),
+		Address:  asString(tuple["addr"]),
+	}
+	frame.Level, _ = strconv.Atoi(asString(tuple["level"]))
+	frame.Line, _ = strconv.Atoi(asString(tuple["line"]))
+
+	return frame
+}
+
+func asString(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+func (s *Session) SetBreakpoint(ctx context.Context, location string) (toolchain.Breakpoint, error) {
+	rec, err := s.execute(ctx, "-break-insert "+location)
+	if err != nil {
+		return toolchain.Breakpoint{}, err
+	}
+
+	bkpt, _ := rec.results["bkpt"].(map[string]any)
+
+	bp := toolchain.Breakpoint{Location: location, File: asString(bkpt["file"])}
+	bp.Number, _ = strconv.Atoi(asString(bkpt["number"]))
+	bp.Line, _ = strconv.Atoi(asString(bkpt["line"]))
+
+	return bp, nil
+}
+
+// Run starts the debugged program with the given arguments.
+//
+// Piping stdin to the inferior requires allocating it a separate controlling terminal
+// (`-inferior-tty-set`), which this Session doesn't set up, so a non-nil stdin is
+// rejected outright rather than silently discarded.
+func (s *Session) Run(ctx context.Context, args []string, stdin io.Reader) error {
+	if stdin != nil {
+		return fmt.Errorf("gcc: piping stdin to the inferior is not supported")
+	}
+
+	if len(args) > 0 {
+		if _, err := s.execute(ctx, "-exec-arguments "+strings.Join(args, " ")); err != nil {
+			return err
+		}
+	}
+
+	_, err := s.execute(ctx, "-exec-run")
+	return err
+}
+
+func (s *Session) Continue(ctx context.Context) error {
+	_, err := s.execute(ctx, "-exec-continue")
+	return err
+}
+
+func (s *Session) Step(ctx context.Context) error {
+	_, err := s.execute(ctx, "-exec-step")
+	return err
+}
+
+func (s *Session) Next(ctx context.Context) error {
+	_, err := s.execute(ctx, "-exec-next")
+	return err
+}
+
+func (s *Session) Finish(ctx context.Context) error {
+	_, err := s.execute(ctx, "-exec-finish")
+	return err
+}
+
+func (s *Session) EvaluateExpression(ctx context.Context, expr string) (string, error) {
+	rec, err := s.execute(ctx, `-data-evaluate-expression "`+expr+`"`)
+	if err != nil {
+		return "", err
+	}
+
+	return asString(rec.results["value"]), nil
+}
+
+func (s *Session) ReadStack(ctx context.Context) ([]toolchain.DebugFrame, error) {
+	rec, err := s.execute(ctx, "-stack-list-frames")
+	if err != nil {
+		return nil, err
+	}
+
+	rawFrames, _ := rec.results["stack"].([]any)
+	frames := make([]toolchain.DebugFrame, 0, len(rawFrames))
+
+	for _, raw := range rawFrames {
+		if f := parseFrame(raw); f != nil {
+			frames = append(frames, *f)
+		}
+	}
+
+	return frames, nil
+}
+
+func (s *Session) ReadLocals(ctx context.Context) ([]toolchain.Variable, error) {
+	// -stack-list-variables, unlike -stack-list-locals, includes the frame's arguments.
+	rec, err := s.execute(ctx, "-stack-list-variables --all-values")
+	if err != nil {
+		return nil, err
+	}
+
+	rawLocals, _ := rec.results["variables"].([]any)
+	locals := make([]toolchain.Variable, 0, len(rawLocals))
+
+	for _, raw := range rawLocals {
+		tuple, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		locals = append(locals, toolchain.Variable{
+			Name:  asString(tuple["name"]),
+			Value: asString(tuple["value"]),
+			Type:  asString(tuple["type"]),
+		})
+	}
+
+	return locals, nil
+}
+
+func (s *Session) Events() <-chan toolchain.DebugEvent {
+	return s.events
+}
+
+func (s *Session) Close() error {
+	_, _ = io.WriteString(s.stdin, "-gdb-exit\n")
+	_ = s.stdin.Close()
+	return s.cmd.Wait()
+}
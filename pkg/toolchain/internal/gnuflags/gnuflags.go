@@ -0,0 +1,190 @@
+/*
+Package gnuflags implements the CompileOptions-to-command-line-arguments translation shared by
+GCC and Clang, whose drivers are otherwise flag-compatible. gcc.CompileArgs and clang.CompileArgs
+are thin wrappers around a Dialect value that supplies what does differ between them: which
+language standards they accept, how flags are rendered, and their error-message prefix.
+*/
+package gnuflags
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tmaxmax/bmoisil/pkg/toolchain"
+)
+
+// exclusiveSanitizers lists the runtime sanitizers GCC and Clang can only instrument one of at a
+// time, because they all intercept the same memory accesses.
+var exclusiveSanitizers = []toolchain.Sanitizer{
+	toolchain.AddressSanitizer,
+	toolchain.ThreadSanitizer,
+	toolchain.MemorySanitizer,
+}
+
+var sanitizerRepresentation = map[toolchain.Sanitizer]string{
+	toolchain.AddressSanitizer:           "address",
+	toolchain.UndefinedBehaviorSanitizer: "undefined",
+	toolchain.ThreadSanitizer:            "thread",
+	toolchain.MemorySanitizer:            "memory",
+	toolchain.LeakSanitizer:              "leak",
+	toolchain.FuzzerSanitizer:            "fuzzer",
+}
+
+// Dialect bundles the pieces that differ between GCC and Clang's command-line flags.
+type Dialect struct {
+	// Flags describes how to render the accumulated toolchain.Flags into arguments.
+	Flags toolchain.FlagDialect
+	// Standards maps the language standards this compiler accepts to their -std= spelling.
+	Standards map[toolchain.CompileLanguageStandard]string
+	// ErrPrefix is the "<tool>" in this dialect's "<tool>: ..." error messages.
+	ErrPrefix string
+}
+
+func (d Dialect) errorf(format string, args ...any) error {
+	return fmt.Errorf(d.ErrPrefix+": "+format, args...)
+}
+
+func (d Dialect) addLanguageStandardFlag(flags toolchain.Flags, standard toolchain.CompileLanguageStandard) error {
+	if standard == toolchain.CompileLanguageStandardDefault {
+		return nil
+	}
+
+	repr, ok := d.Standards[standard]
+	if !ok {
+		return d.errorf("unsupported language standard")
+	}
+
+	flags.Set("std", repr)
+
+	return nil
+}
+
+func addOptimizationFlags(flags toolchain.Flags, optimization toolchain.CompileOptimizationLevel) {
+	switch optimization {
+	case toolchain.CompileOptimizationNone:
+		flags.Set("O", "0")
+	case toolchain.CompileOptimizationModerate:
+		flags.Set("O", "1")
+	case toolchain.CompileOptimizationAggressive:
+		flags.Set("O", "2")
+	case toolchain.CompileOptimizationDebug:
+		flags.Set("O", "g")
+		flags.Toggle("ggdb")
+	}
+}
+
+func addSourceKindFlag(flags toolchain.Flags, kind toolchain.SourceFileKind) {
+	if kind == toolchain.SourceFileKindC {
+		flags.Set("x", "c")
+	} else {
+		flags.Set("x", "c++")
+	}
+}
+
+func (d Dialect) addSanitizerFlags(flags toolchain.Flags, sanitizers []toolchain.Sanitizer) error {
+	if len(sanitizers) == 0 {
+		return nil
+	}
+
+	exclusiveCount := 0
+	names := make([]string, 0, len(sanitizers))
+
+	for _, s := range sanitizers {
+		for _, e := range exclusiveSanitizers {
+			if s == e {
+				exclusiveCount++
+			}
+		}
+
+		names = append(names, sanitizerRepresentation[s])
+	}
+
+	if exclusiveCount > 1 {
+		return d.errorf("address, thread and memory sanitizers are mutually exclusive")
+	}
+
+	flags.Set("fsanitize", strings.Join(names, ","))
+	// Sanitizer backtraces are far more useful with frame pointers kept around, and the
+	// instrumentation already dwarfs the performance cost of disabling this optimization.
+	flags.Toggle("fno-omit-frame-pointer")
+
+	return nil
+}
+
+func addStackProtectorFlag(flags toolchain.Flags, level toolchain.StackProtectorLevel) {
+	switch level {
+	case toolchain.StackProtectorStrong:
+		flags.Toggle("fstack-protector-strong")
+	case toolchain.StackProtectorAll:
+		flags.Toggle("fstack-protector-all")
+	}
+}
+
+func addSpectreMitigationFlag(flags toolchain.Flags, enabled bool) {
+	if enabled {
+		flags.Set("mindirect-branch", "thunk")
+		flags.Toggle("mfunction-return=thunk")
+	}
+}
+
+func addFortifySourceFlag(flags toolchain.Flags, level int) {
+	if level <= 0 {
+		return
+	}
+
+	flags.Add("D", fmt.Sprintf("_FORTIFY_SOURCE=%d", level))
+}
+
+func addWarningsFlags(flags toolchain.Flags, warnings toolchain.Warnings) {
+	if warnings&toolchain.WarningsAll != 0 {
+		flags.Toggle("Wall")
+	}
+	if warnings&toolchain.WarningsExtra != 0 {
+		flags.Toggle("Wextra")
+	}
+	if warnings&toolchain.WarningsPedantic != 0 {
+		flags.Toggle("Wpedantic")
+	}
+	if warnings&toolchain.WarningsAsErrors != 0 {
+		flags.Toggle("Werror")
+	}
+}
+
+// ParseOptions translates opts into the command-line arguments d's compiler would use to
+// produce outputPath, without invoking the compiler. gcc.CompileArgs and clang.CompileArgs are
+// both thin wrappers around this.
+func (d Dialect) ParseOptions(outputPath string, opts *toolchain.CompileOptions) ([]string, error) {
+	flags := make(toolchain.Flags)
+	flags.Set("o", outputPath)
+
+	if opts == nil {
+		flags.Set("x", "c++")
+		return d.Flags.Render(flags), nil
+	}
+
+	flags.Set("D", opts.Defines...)
+	flags.Set("U", opts.Undefs...)
+	flags.Set("L", opts.LibraryPaths...)
+	flags.Set("l", opts.Libraries...)
+	flags.Set("I", opts.IncludePaths...)
+
+	if err := d.addLanguageStandardFlag(flags, opts.LanguageStandard); err != nil {
+		return nil, err
+	}
+
+	addOptimizationFlags(flags, opts.OptimizationLevel)
+	addSourceKindFlag(flags, opts.SourceFileKind)
+
+	if err := d.addSanitizerFlags(flags, opts.Sanitizers); err != nil {
+		return nil, err
+	}
+
+	addStackProtectorFlag(flags, opts.StackProtector)
+	addSpectreMitigationFlag(flags, opts.SpectreMitigation)
+	addFortifySourceFlag(flags, opts.FortifySource)
+	addWarningsFlags(flags, opts.Warnings)
+
+	flags.Merge(opts.Flags)
+
+	return d.Flags.Render(flags), nil
+}
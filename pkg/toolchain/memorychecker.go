@@ -0,0 +1,216 @@
+package toolchain
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// A MemoryChecker runs a compiled executable under a memory-error detection tool
+// and reports the errors found during the run.
+type MemoryChecker interface {
+	// Check runs the executable under the memory checker and analyzes its
+	// behavior, according to the given options. The options may be nil.
+	Check(ctx context.Context, executablePath string, options *CheckOptions) (*CheckReport, error)
+	// Info returns some information about the memory checker.
+	Info() MemoryCheckerInfo
+}
+
+// CheckOptions customizes how a MemoryChecker analyzes an executable.
+type CheckOptions struct {
+	// Args are passed to the executable under test.
+	Args []string
+	// Dir, if non-empty, is the working directory the executable is run in. Set this to the
+	// same sandboxed directory the executable already ran in when the check should see the
+	// exact files (e.g. a file-based test case input) that run produced or consumed.
+	Dir string
+	// Stdin, if non-nil, is piped to the executable's standard input.
+	Stdin io.Reader
+	// Suppressions lists the suppression files used to silence known, accepted errors.
+	Suppressions []string
+	// TrackOrigins enables tracking the origin of uninitialized values. This gives
+	// more precise reports at a significant performance cost.
+	TrackOrigins bool
+	// LeakCheck selects how thoroughly leaked memory blocks are reported.
+	LeakCheck LeakCheckMode
+}
+
+// LeakCheckMode values select how thoroughly a MemoryChecker looks for and reports memory leaks.
+type LeakCheckMode int
+
+const (
+	// LeakCheckNone disables leak detection.
+	LeakCheckNone LeakCheckMode = iota
+	// LeakCheckSummary only reports the amount of leaked memory, without individual leak reports.
+	LeakCheckSummary
+	// LeakCheckFull reports every individual leaked memory block, with its allocation stack.
+	LeakCheckFull
+)
+
+// MemoryErrorKind identifies the category of a MemoryError.
+type MemoryErrorKind string
+
+// StackFrame describes a single frame of a MemoryError's backtrace.
+type StackFrame struct {
+	// InstructionPointer at which this frame was captured.
+	InstructionPointer string
+	// Object (executable or shared library) the instruction pointer belongs to.
+	Object string
+	// Function the instruction pointer belongs to. May be empty if no debug symbols are present.
+	Function string
+	// Directory containing the source file, if known from debug information.
+	Directory string
+	// File containing the source line, if known from debug information.
+	File string
+	// Line number within File, if known from debug information.
+	Line int
+}
+
+// MemoryError describes a single error detected while checking an executable.
+type MemoryError struct {
+	// Kind of the error, as reported by the memory checker.
+	Kind MemoryErrorKind
+	// What is a human-readable description of the error.
+	What string
+	// Stack is the backtrace at which the error was detected, innermost frame first.
+	Stack []StackFrame
+}
+
+// LeakSummary aggregates the memory leaked by the checked executable, in bytes.
+type LeakSummary struct {
+	DefinitelyLost  int64
+	IndirectlyLost  int64
+	PossiblyLost    int64
+	StillReachable  int64
+	SuppressedBytes int64
+}
+
+// CheckReport holds the result of running an executable through a MemoryChecker.
+type CheckReport struct {
+	// Errors found during the run, in report order.
+	Errors []MemoryError
+	// Leak summarizes the memory leaked by the executable, if leak checking was enabled.
+	Leak LeakSummary
+	// ExitCode of the checked executable.
+	ExitCode int
+}
+
+// MemoryCheckerInfo holds some information about the underlying memory checker.
+type MemoryCheckerInfo struct {
+	// Name of the memory checker.
+	Name string
+	// Path of the memory checker's executable.
+	Path string
+	// Version number of the memory checker.
+	Version string
+}
+
+// NewMemoryChecker looks up the memory checker's executable with the given name on the host
+// and initializes a MemoryChecker instance that uses that executable.
+func NewMemoryChecker(name string) (MemoryChecker, error) {
+	memoryCheckersMutex.RLock()
+	constructor := memoryCheckers[name]
+	memoryCheckersMutex.RUnlock()
+
+	if constructor == nil {
+		return nil, fmt.Errorf("toolchain: missing memory checker %q, forgotten import?", name)
+	}
+
+	checker, err := constructor(name)
+	if err != nil {
+		return nil, fmt.Errorf("toolchain: failed to initialize memory checker %q: %w", name, err)
+	}
+
+	return checker, nil
+}
+
+// DetectMemoryCheckers returns all the supported (imported) memory checkers available on the host system.
+func DetectMemoryCheckers() []MemoryChecker {
+	memoryCheckersMutex.RLock()
+	defer memoryCheckersMutex.RUnlock()
+
+	return detectMemoryCheckers()
+}
+
+func detectMemoryCheckers() []MemoryChecker {
+	var found []MemoryChecker
+
+	for _, name := range memoryCheckersNames {
+		checker, err := memoryCheckers[name](name)
+		if err != nil {
+			continue
+		}
+
+		found = append(found, checker)
+	}
+
+	return found
+}
+
+// UsePreferredMemoryChecker tries to initialize the memory checker specified by the MEMCHECK
+// environment variable. If MEMCHECK is empty, it falls back to the first value returned by
+// DetectMemoryCheckers. If no memory checker was detected, it returns an error.
+func UsePreferredMemoryChecker() (MemoryChecker, error) {
+	memoryCheckersMutex.RLock()
+	defer memoryCheckersMutex.RUnlock()
+
+	name := os.Getenv("MEMCHECK")
+	if name != "" {
+		for _, registeredName := range memoryCheckersNames {
+			if strings.Contains(name, registeredName) {
+				checker, err := memoryCheckers[registeredName](name)
+				if err != nil {
+					break
+				}
+
+				return checker, nil
+			}
+		}
+	}
+
+	checkers := detectMemoryCheckers()
+	if len(checkers) == 0 {
+		return nil, fmt.Errorf("toolchain: no memory checkers registered, forgotten imports?")
+	}
+
+	return checkers[0], nil
+}
+
+// MemoryCheckerConstructor is a function that constructs a MemoryChecker from an executable.
+// It takes either a path to the executable or the executable's name as an argument.
+type MemoryCheckerConstructor func(pathOrExecutableName string) (MemoryChecker, error)
+
+var (
+	memoryCheckers      = map[string]MemoryCheckerConstructor{}
+	memoryCheckersNames []string // provide ordered iteration for the map
+	memoryCheckersMutex sync.RWMutex
+)
+
+// RegisterMemoryChecker adds a custom MemoryChecker implementation for usage.
+// If an implementation with the same name already exists or the provided
+// constructor is nil, this function panics. If the name has path separators
+// or path list separators, this function panics.
+//
+// The provided name may be used by the constructor to look up the path of the memory checker's executable.
+func RegisterMemoryChecker(name string, constructor MemoryCheckerConstructor) {
+	memoryCheckersMutex.Lock()
+	defer memoryCheckersMutex.Unlock()
+
+	if !isValidImplementationName(name) {
+		panic(fmt.Sprintf("toolchain: memory checker name %q has invalid characters", name))
+	}
+
+	if memoryCheckers[name] != nil {
+		panic(fmt.Sprintf("toolchain: memory checker %q is already registered", name))
+	}
+
+	if constructor == nil {
+		panic(fmt.Sprintf("toolchain: constructor provided for memory checker %q is nil", name))
+	}
+
+	memoryCheckers[name] = constructor
+	memoryCheckersNames = append(memoryCheckersNames, name)
+}
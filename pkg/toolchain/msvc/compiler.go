@@ -0,0 +1,281 @@
+package msvc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/tmaxmax/bmoisil/pkg/toolchain"
+)
+
+var standardsRepresentation = map[toolchain.CompileLanguageStandard]string{
+	toolchain.CompileLanguageStandardC11:   "c11",
+	toolchain.CompileLanguageStandardC17:   "c17",
+	toolchain.CompileLanguageStandardCPP14: "c++14",
+	toolchain.CompileLanguageStandardCPP17: "c++17",
+	toolchain.CompileLanguageStandardCPP20: "c++20",
+	toolchain.CompileLanguageStandardCPP23: "c++23",
+}
+
+// flagDialect describes how cl.exe renders CompileOptions into command-line arguments.
+// Unlike GCC/Clang, cl.exe's flags are prefixed with "/" (though "-" is also accepted),
+// and most flags take their value joined by ":" rather than "=" or bare concatenation.
+var flagDialect = toolchain.FlagDialect{
+	Prefix: "/",
+	JoinStyles: map[string]toolchain.JoinStyle{
+		"D":       toolchain.JoinConcat,
+		"I":       toolchain.JoinConcat,
+		"std":     toolchain.JoinColon,
+		"Fe":      toolchain.JoinColon,
+		"LIBPATH": toolchain.JoinColon,
+	},
+}
+
+func addLanguageStandardFlag(flags toolchain.Flags, standard toolchain.CompileLanguageStandard) error {
+	if standard == toolchain.CompileLanguageStandardDefault {
+		return nil
+	}
+
+	standardRepr, ok := standardsRepresentation[standard]
+	if !ok {
+		return fmt.Errorf("msvc: unsupported language standard")
+	}
+
+	flags.Set("std", standardRepr)
+
+	return nil
+}
+
+func addOptimizationFlags(flags toolchain.Flags, optimization toolchain.CompileOptimizationLevel) {
+	switch optimization {
+	case toolchain.CompileOptimizationNone:
+		flags.Toggle("Od")
+	case toolchain.CompileOptimizationModerate:
+		flags.Toggle("O1")
+	case toolchain.CompileOptimizationAggressive:
+		flags.Toggle("O2")
+	case toolchain.CompileOptimizationDebug:
+		flags.Toggle("Od")
+		flags.Toggle("Z7")
+	}
+}
+
+func addSourceKindFlag(flags toolchain.Flags, kind toolchain.SourceFileKind) {
+	if kind == toolchain.SourceFileKindC {
+		flags.Toggle("TC")
+	} else {
+		flags.Toggle("TP")
+	}
+}
+
+func sourceFileExtension(kind toolchain.SourceFileKind) string {
+	if kind == toolchain.SourceFileKindC {
+		return ".c"
+	}
+
+	return ".cpp"
+}
+
+var sanitizerRepresentation = map[toolchain.Sanitizer]string{
+	toolchain.AddressSanitizer: "address",
+}
+
+func addSanitizerFlags(flags toolchain.Flags, sanitizers []toolchain.Sanitizer) error {
+	if len(sanitizers) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(sanitizers))
+
+	for _, s := range sanitizers {
+		repr, ok := sanitizerRepresentation[s]
+		if !ok {
+			return fmt.Errorf("msvc: unsupported sanitizer")
+		}
+
+		names = append(names, repr)
+	}
+
+	flags.Set("fsanitize", strings.Join(names, ","))
+	// /Oy- keeps frame pointers around, MSVC's equivalent of GCC/Clang's
+	// -fno-omit-frame-pointer, so ASan's backtraces stay useful.
+	flags.Toggle("Oy-")
+
+	return nil
+}
+
+func addWarningsFlags(flags toolchain.Flags, warnings toolchain.Warnings) {
+	// MSVC has no direct equivalent of -Wextra or -Wpedantic: /W4 is the closest it gets to
+	// "all commonly-useful warnings", and there is no flag to warn on non-standard extensions.
+	if warnings&(toolchain.WarningsAll|toolchain.WarningsExtra) != 0 {
+		flags.Toggle("W4")
+	}
+	if warnings&toolchain.WarningsAsErrors != 0 {
+		flags.Toggle("WX")
+	}
+}
+
+func addStackProtectorFlag(flags toolchain.Flags, level toolchain.StackProtectorLevel) {
+	// MSVC's buffer security check is a single on/off switch (/GS, /GS-), unlike GCC/Clang's
+	// graduated -fstack-protector levels, so StackProtectorStrong and StackProtectorAll both
+	// just mean "enabled" here.
+	if level == toolchain.StackProtectorNone {
+		flags.Toggle("GS-")
+	}
+}
+
+func addSpectreMitigationFlag(flags toolchain.Flags, enabled bool) {
+	if enabled {
+		flags.Toggle("Qspectre")
+	}
+}
+
+// CompileArgs translates opts into the cl.exe command-line arguments that would be used
+// to produce outputPath, without invoking the compiler and without the source file
+// argument that Compile appends. It is exported so that cross-backend flag translation
+// can be tested without a cl.exe executable on the host.
+func CompileArgs(outputPath string, opts *toolchain.CompileOptions) ([]string, error) {
+	return parseOptions(outputPath, opts)
+}
+
+func parseOptions(outputPath string, opts *toolchain.CompileOptions) ([]string, error) {
+	flags := make(toolchain.Flags)
+	flags.Set("Fe", outputPath)
+
+	if opts == nil {
+		flags.Toggle("TP")
+		return flagDialect.Render(flags), nil
+	}
+
+	flags.Set("D", opts.Defines...)
+	flags.Set("I", opts.IncludePaths...)
+
+	if err := addLanguageStandardFlag(flags, opts.LanguageStandard); err != nil {
+		return nil, err
+	}
+
+	addOptimizationFlags(flags, opts.OptimizationLevel)
+	addSourceKindFlag(flags, opts.SourceFileKind)
+
+	if err := addSanitizerFlags(flags, opts.Sanitizers); err != nil {
+		return nil, err
+	}
+
+	addStackProtectorFlag(flags, opts.StackProtector)
+	addSpectreMitigationFlag(flags, opts.SpectreMitigation)
+	addWarningsFlags(flags, opts.Warnings)
+
+	if len(opts.LibraryPaths) > 0 {
+		flags.Set("LIBPATH", opts.LibraryPaths...)
+	}
+
+	flags.Merge(opts.Flags)
+
+	args := flagDialect.Render(flags)
+
+	// cl.exe links against libraries by naming their .lib files directly on the command
+	// line, not through a flag, so these are appended after the flags rather than folded
+	// into the dialect above.
+	for _, lib := range opts.Libraries {
+		if !strings.HasSuffix(lib, ".lib") {
+			lib += ".lib"
+		}
+		args = append(args, lib)
+	}
+
+	return args, nil
+}
+
+func parseVersion(banner []byte) string {
+	const marker = "Version "
+
+	idx := bytes.Index(banner, []byte(marker))
+	if idx == -1 {
+		return ""
+	}
+
+	rest := banner[idx+len(marker):]
+	end := bytes.IndexAny(rest, " \r\n")
+	if end == -1 {
+		end = len(rest)
+	}
+
+	return string(rest[:end])
+}
+
+type Compiler struct {
+	info toolchain.CompilerInfo
+}
+
+var _ toolchain.Compiler = (*Compiler)(nil)
+
+// NewCompiler creates a cl.exe compiler instance. It looks up an executable using the
+// provided name or uses the executable at the given path, if a path is specified.
+func NewCompiler(pathOrExec string) (*Compiler, error) {
+	cmd := execCommandContext(context.Background(), pathOrExec)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	// cl.exe with no arguments prints its version banner to stderr and exits non-zero,
+	// since it was given no source file to compile; only the banner is of interest here.
+	_ = cmd.Run()
+
+	version := parseVersion(stderr.Bytes())
+	if version == "" {
+		return nil, fmt.Errorf("msvc: failed to initialize compiler: could not determine version")
+	}
+
+	info := toolchain.CompilerInfo{
+		Name:    compilerName,
+		Path:    cmd.Path,
+		Version: version,
+	}
+
+	return &Compiler{info: info}, nil
+}
+
+func (c *Compiler) Compile(ctx context.Context, input io.Reader, outputPath string, opts *toolchain.CompileOptions) error {
+	args, err := parseOptions(outputPath, opts)
+	if err != nil {
+		return fmt.Errorf("msvc: %w", err)
+	}
+
+	kind := toolchain.SourceFileKindCPP
+	if opts != nil {
+		kind = opts.SourceFileKind
+	}
+
+	// cl.exe cannot compile from standard input like g++/clang++'s "-x <lang> -" trick,
+	// so the source is spooled to a temporary file with the right extension first.
+	srcFile, err := os.CreateTemp("", "msvc-src-*"+sourceFileExtension(kind))
+	if err != nil {
+		return fmt.Errorf("msvc: failed to create temporary source file: %w", err)
+	}
+	defer os.Remove(srcFile.Name())
+
+	if _, err := io.Copy(srcFile, input); err != nil {
+		srcFile.Close()
+		return fmt.Errorf("msvc: failed to write temporary source file: %w", err)
+	}
+
+	if err := srcFile.Close(); err != nil {
+		return fmt.Errorf("msvc: failed to write temporary source file: %w", err)
+	}
+
+	args = append(args, srcFile.Name())
+	cmd := execCommandContext(ctx, c.info.Path, args...)
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("msvc: failed to compile: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Compiler) Info() toolchain.CompilerInfo {
+	return c.info
+}
@@ -0,0 +1,25 @@
+/*
+Package msvc provides a compiler implementation that uses the installed
+Microsoft Visual C++ toolchain (cl.exe) on the host system.
+
+It registers the cl compiler. MSVC does not expose a debugger or memory
+checker implementation compatible with this module's interfaces, so this
+package leaves RecommendedDebugger and RecommendedMemoryChecker unset.
+*/
+package msvc
+
+import (
+	"os/exec"
+
+	"github.com/tmaxmax/bmoisil/pkg/toolchain"
+)
+
+const compilerName = "cl"
+
+var execCommandContext = exec.CommandContext
+
+func init() {
+	toolchain.RegisterCompiler(compilerName, func(pathOrExecutableName string) (toolchain.Compiler, error) {
+		return NewCompiler(pathOrExecutableName)
+	})
+}
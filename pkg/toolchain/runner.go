@@ -0,0 +1,219 @@
+package toolchain
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tmaxmax/bmoisil/pkg/pbinfo"
+)
+
+// Verdict classifies the outcome of running a solution against a single test case.
+type Verdict int
+
+const (
+	// AC means the solution ran within the limits and its output matched the test case.
+	AC Verdict = iota
+	// WA means the solution ran within the limits but produced the wrong output.
+	WA
+	// TLE means the solution did not finish within the runner's MaxTime.
+	TLE
+	// MLE means the solution exceeded the runner's MaxMemoryBytes.
+	MLE
+	// RE means the solution exited with a non-zero status or crashed.
+	RE
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case AC:
+		return "AC"
+	case WA:
+		return "WA"
+	case TLE:
+		return "TLE"
+	case MLE:
+		return "MLE"
+	case RE:
+		return "RE"
+	default:
+		return "unknown"
+	}
+}
+
+// RunStats holds what was observed about a single sandboxed run.
+type RunStats struct {
+	// WallTime the process took to run.
+	WallTime time.Duration
+	// CPUTime the process used, as reported by the OS.
+	CPUTime time.Duration
+	// MemoryBytes is the peak resident set size used by the process.
+	MemoryBytes int64
+}
+
+// A Runner runs a compiled binary against pbinfo.TestCases, enforcing the time and
+// memory limits configured on it and comparing the produced output with Compare (byte
+// for byte by default). Callers that need a memory checker pass should run the cases
+// through package judge instead, which builds on Runner.
+type Runner struct {
+	// MaxTime the process is allowed to run for. Zero means no limit.
+	MaxTime time.Duration
+	// MaxMemoryBytes the process is allowed to use. Zero means no limit.
+	MaxMemoryBytes int64
+	// MaxStackBytes the process's stack is allowed to grow to. Zero means no limit.
+	MaxStackBytes int64
+	// InputFromStdin indicates the test case's input should be piped to the process's
+	// standard input rather than written to a file.
+	InputFromStdin bool
+	// OutputRelPath, if non-empty, is read relative to the run's working directory after
+	// the process exits, instead of capturing standard output.
+	OutputRelPath string
+	// InputRelPath names the file the test case's input is written to, when InputFromStdin
+	// is false.
+	InputRelPath string
+	// Compare decides whether the process's actual output matches the test case's expected
+	// output. Defaults to bytes.Equal.
+	Compare func(expected, actual []byte) bool
+}
+
+// defaultSanitizerMemoryMultiplier is applied to MaxMemoryBytes by WithSanitizers when no
+// multiplier is given. It is a conservative estimate of AddressSanitizer's overhead, which
+// is the most memory-hungry of the sanitizers Runner is likely to see in practice.
+const defaultSanitizerMemoryMultiplier = 3
+
+// WithSanitizers returns a copy of r with MaxMemoryBytes scaled by multiplier, to account
+// for the extra memory overhead a sanitizer's runtime instrumentation adds on top of a
+// binary's normal footprint. If sanitizers is empty or r.MaxMemoryBytes is zero (no limit),
+// r is returned unchanged. A multiplier <= 0 falls back to defaultSanitizerMemoryMultiplier.
+func (r *Runner) WithSanitizers(sanitizers []Sanitizer, multiplier float64) *Runner {
+	if len(sanitizers) == 0 || r.MaxMemoryBytes == 0 {
+		return r
+	}
+
+	if multiplier <= 0 {
+		multiplier = defaultSanitizerMemoryMultiplier
+	}
+
+	clone := *r
+	clone.MaxMemoryBytes = int64(float64(r.MaxMemoryBytes) * multiplier)
+
+	return &clone
+}
+
+// Run runs exePath against a single test case, under the resource limits configured on r, in a
+// freshly created sandboxed working directory that is removed once Run returns. Callers that
+// need to reuse that same directory — for example to rerun exePath under a MemoryChecker against
+// the exact input file the case ran against — should use RunKeepDir instead.
+func (r *Runner) Run(ctx context.Context, exePath string, tc pbinfo.TestCase) (Verdict, RunStats, error) {
+	verdict, stats, dir, err := r.run(ctx, exePath, tc)
+	if dir != "" {
+		os.RemoveAll(dir)
+	}
+
+	return verdict, stats, err
+}
+
+// RunKeepDir behaves like Run, but leaves the sandboxed working directory it created in place
+// and returns its path instead of removing it. The caller is responsible for removing dir once
+// done with it.
+func (r *Runner) RunKeepDir(ctx context.Context, exePath string, tc pbinfo.TestCase) (verdict Verdict, stats RunStats, dir string, err error) {
+	return r.run(ctx, exePath, tc)
+}
+
+func (r *Runner) run(ctx context.Context, exePath string, tc pbinfo.TestCase) (Verdict, RunStats, string, error) {
+	dir, err := os.MkdirTemp("", "runner-*")
+	if err != nil {
+		return 0, RunStats{}, "", fmt.Errorf("toolchain: failed to create working directory: %w", err)
+	}
+
+	cfg := &runConfig{
+		exePath:   exePath,
+		workDir:   dir,
+		maxTime:   r.MaxTime,
+		maxMemory: r.MaxMemoryBytes,
+		maxStack:  r.MaxStackBytes,
+	}
+
+	if r.InputFromStdin {
+		cfg.stdin = tc.Input
+	} else if r.InputRelPath != "" {
+		if err := os.WriteFile(filepath.Join(dir, r.InputRelPath), tc.Input, 0o644); err != nil {
+			os.RemoveAll(dir)
+			return 0, RunStats{}, "", fmt.Errorf("toolchain: failed to write input file: %w", err)
+		}
+	}
+
+	cfg.outputRelPath = r.OutputRelPath
+
+	result, err := runProcess(ctx, cfg)
+	if err != nil {
+		os.RemoveAll(dir)
+		return 0, RunStats{}, "", err
+	}
+
+	stats := RunStats{
+		WallTime:    result.wallTime,
+		CPUTime:     result.cpuTime,
+		MemoryBytes: result.maxRSS,
+	}
+
+	switch {
+	case result.timedOut:
+		return TLE, stats, dir, nil
+	case result.memoryExceeded:
+		return MLE, stats, dir, nil
+	case result.exitErr != nil:
+		return RE, stats, dir, nil
+	}
+
+	compare := r.Compare
+	if compare == nil {
+		compare = bytes.Equal
+	}
+
+	if !compare(tc.Output, result.output) {
+		return WA, stats, dir, nil
+	}
+
+	return AC, stats, dir, nil
+}
+
+// RunAllResult is a single verdict streamed by RunAll.
+type RunAllResult struct {
+	// Case is the index of the test case this result is for, within the slice passed to RunAll.
+	Case int
+	Verdict
+	RunStats
+	Err error
+}
+
+// RunAll runs exePath against every one of the given test cases, in order, streaming a
+// RunAllResult for each one as soon as it is available. The returned channel is closed
+// once every case has run, ctx is done, or a case fails to even start running.
+func (r *Runner) RunAll(ctx context.Context, exePath string, cases []pbinfo.TestCase) <-chan RunAllResult {
+	out := make(chan RunAllResult)
+
+	go func() {
+		defer close(out)
+
+		for i, tc := range cases {
+			verdict, stats, err := r.Run(ctx, exePath, tc)
+			res := RunAllResult{Case: i, Verdict: verdict, RunStats: stats, Err: err}
+
+			select {
+			case out <- res:
+			case <-ctx.Done():
+				return
+			}
+
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,118 @@
+package toolchain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// runConfig describes a single sandboxed invocation of a compiled solution.
+type runConfig struct {
+	exePath   string
+	workDir   string
+	maxTime   time.Duration
+	maxMemory int64
+	maxStack  int64
+	stdin     []byte
+	// outputRelPath, if non-empty, is read from workDir after the process exits instead
+	// of capturing stdout.
+	outputRelPath string
+}
+
+// runResult holds what was observed about a single sandboxed run.
+type runResult struct {
+	output         []byte
+	wallTime       time.Duration
+	cpuTime        time.Duration
+	maxRSS         int64
+	timedOut       bool
+	memoryExceeded bool
+	exitErr        error
+}
+
+// runProcess runs cfg.exePath under the resource limits described by cfg, using the
+// netstack-style deadline pattern: a cancelCh is closed by a time.AfterFunc timer when
+// the wall-clock limit is hit, and a goroutine races the process's exit against it so a
+// single ctx deadline composes cleanly with the per-case time limit without leaking timers.
+func runProcess(ctx context.Context, cfg *runConfig) (*runResult, error) {
+	cmd := newCommand(ctx, cfg)
+	cmd.Dir = cfg.workDir
+
+	if cfg.stdin != nil {
+		cmd.Stdin = bytes.NewReader(cfg.stdin)
+	}
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	var timer *time.Timer
+	cancelCh := make(chan struct{})
+	if cfg.maxTime > 0 {
+		timer = time.AfterFunc(cfg.maxTime, func() { close(cancelCh) })
+		defer timer.Stop()
+	}
+
+	start := time.Now()
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("toolchain: failed to start process: %w", err)
+	}
+
+	waitCh := make(chan error, 1)
+	go func() { waitCh <- cmd.Wait() }()
+
+	result := &runResult{}
+
+	select {
+	case err := <-waitCh:
+		result.exitErr = classifyExit(err)
+	case <-cancelCh:
+		result.timedOut = true
+		killProcessGroup(cmd)
+		<-waitCh
+	case <-ctx.Done():
+		killProcessGroup(cmd)
+		<-waitCh
+		return nil, ctx.Err()
+	}
+
+	result.wallTime = time.Since(start)
+	result.output = stdout.Bytes()
+
+	if cfg.outputRelPath != "" && !result.timedOut {
+		// Standard output doesn't count here: a missing output file reads as empty output.
+		result.output = nil
+		if data, err := os.ReadFile(filepath.Join(cfg.workDir, cfg.outputRelPath)); err == nil {
+			result.output = data
+		}
+	}
+
+	if state := cmd.ProcessState; state != nil {
+		result.cpuTime = state.UserTime() + state.SystemTime()
+		result.maxRSS = peakRSS(state)
+
+		if cfg.maxMemory > 0 && result.maxRSS > cfg.maxMemory {
+			result.memoryExceeded = true
+		}
+	}
+
+	return result, nil
+}
+
+func classifyExit(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return fmt.Errorf("exited with status %d", exitErr.ExitCode())
+	}
+
+	return err
+}
@@ -0,0 +1,32 @@
+//go:build !linux
+
+package toolchain
+
+import (
+	"context"
+	"os"
+	"os/exec"
+)
+
+// newCommand builds the command that will run cfg.exePath. Outside Linux there is no
+// setrlimit-equivalent wired up here, so cfg.maxMemory/cfg.maxStack are not enforced;
+// Runner.Run still honors cfg.maxTime via its own deadline handling.
+func newCommand(ctx context.Context, cfg *runConfig) *exec.Cmd {
+	return exec.CommandContext(ctx, cfg.exePath)
+}
+
+// killProcessGroup kills just the process itself, since process groups are a POSIX
+// concept; it won't reach any children the solution spawned.
+func killProcessGroup(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+
+	_ = cmd.Process.Kill()
+}
+
+// peakRSS always reports 0 outside Linux: no platform-specific rusage parsing is done
+// here, so a run is never classified as exceeding its memory limit on these platforms.
+func peakRSS(state *os.ProcessState) int64 {
+	return 0
+}
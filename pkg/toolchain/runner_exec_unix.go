@@ -0,0 +1,77 @@
+//go:build linux
+
+package toolchain
+
+import (
+	"context"
+	"math"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// newCommand builds the command that will run cfg.exePath, applying cfg.maxMemory,
+// cfg.maxStack and a CPU time backstop via setrlimit.
+//
+// Go cannot run arbitrary code between fork and exec in the child (it doesn't fork at
+// all in Go-land; see os/exec), so there is no SysProcAttr hook to call setrlimit
+// ourselves before the target runs. Instead, the target is wrapped in a shell that
+// applies the limits with `ulimit` before exec-ing it, which ends up invoking the same
+// setrlimit(2) syscalls from within the child.
+func newCommand(ctx context.Context, cfg *runConfig) *exec.Cmd {
+	script := ulimitScript(cfg)
+
+	var cmd *exec.Cmd
+	if script == "" {
+		cmd = exec.CommandContext(ctx, cfg.exePath)
+	} else {
+		cmd = exec.CommandContext(ctx, "/bin/sh", "-c", script+`exec "$0"`, cfg.exePath)
+	}
+
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	return cmd
+}
+
+func ulimitScript(cfg *runConfig) string {
+	script := ""
+
+	if cfg.maxMemory > 0 {
+		script += "ulimit -v " + strconv.FormatInt(cfg.maxMemory/1024, 10) + "; "
+	}
+	if cfg.maxStack > 0 {
+		script += "ulimit -s " + strconv.FormatInt(cfg.maxStack/1024, 10) + "; "
+	}
+	if cfg.maxTime > 0 {
+		// Round up, so the CPU limit never fires before the wall-clock limit does.
+		seconds := int64(math.Ceil(cfg.maxTime.Seconds()))
+		if seconds < 1 {
+			seconds = 1
+		}
+		script += "ulimit -t " + strconv.FormatInt(seconds, 10) + "; "
+	}
+
+	return script
+}
+
+// killProcessGroup sends SIGKILL to the whole process group started for cmd, so that
+// any children it spawned are killed along with it.
+func killProcessGroup(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+
+	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
+
+// peakRSS extracts the peak resident set size, in bytes, from the process's rusage.
+// On Linux, Rusage.Maxrss is reported in kilobytes.
+func peakRSS(state *os.ProcessState) int64 {
+	rusage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return 0
+	}
+
+	return rusage.Maxrss * 1024
+}
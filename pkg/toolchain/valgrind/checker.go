@@ -0,0 +1,126 @@
+package valgrind
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/tmaxmax/bmoisil/pkg/toolchain"
+)
+
+// MemoryChecker drives Memcheck, the memory error detector bundled with Valgrind.
+type MemoryChecker struct {
+	info toolchain.MemoryCheckerInfo
+}
+
+var _ toolchain.MemoryChecker = (*MemoryChecker)(nil)
+
+// NewMemoryChecker creates a valgrind memory checker instance. It looks up an executable using
+// the provided name or uses the executable at the given path, if a path is specified.
+func NewMemoryChecker(nameOrPath string) (*MemoryChecker, error) {
+	cmd := execCommandContext(context.Background(), nameOrPath, "--version")
+	version, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("valgrind: failed to initialize memory checker: %w", err)
+	}
+
+	info := toolchain.MemoryCheckerInfo{
+		Name:    checkerName,
+		Path:    cmd.Path,
+		Version: string(bytes.TrimSpace(version)),
+	}
+
+	return &MemoryChecker{info: info}, nil
+}
+
+func (m *MemoryChecker) Info() toolchain.MemoryCheckerInfo {
+	return m.info
+}
+
+func parseOptions(opts *toolchain.CheckOptions) []string {
+	args := []string{"--tool=memcheck"}
+
+	if opts == nil {
+		return args
+	}
+
+	if opts.TrackOrigins {
+		args = append(args, "--track-origins=yes")
+	}
+
+	switch opts.LeakCheck {
+	case toolchain.LeakCheckNone:
+		args = append(args, "--leak-check=no")
+	case toolchain.LeakCheckSummary:
+		args = append(args, "--leak-check=summary")
+	case toolchain.LeakCheckFull:
+		args = append(args, "--leak-check=full")
+	}
+
+	for _, suppression := range opts.Suppressions {
+		args = append(args, "--suppressions="+suppression)
+	}
+
+	return args
+}
+
+// Check runs the executable under Memcheck and parses its XML error report, according to the
+// given options. The options may be nil.
+//
+// The report is streamed back over a pipe passed to Valgrind as --xml-fd, rather than written
+// to a temporary file, so Check works even when the working directory isn't writable.
+func (m *MemoryChecker) Check(ctx context.Context, executablePath string, opts *toolchain.CheckOptions) (*toolchain.CheckReport, error) {
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("valgrind: failed to create report pipe: %w", err)
+	}
+	defer pr.Close()
+
+	args := parseOptions(opts)
+	args = append(args, "--xml=yes", "--xml-fd=3", executablePath)
+
+	if opts != nil {
+		args = append(args, opts.Args...)
+	}
+
+	cmd := execCommandContext(ctx, m.info.Path, args...)
+	cmd.ExtraFiles = []*os.File{pw}
+	if opts != nil {
+		cmd.Stdin = opts.Stdin
+		cmd.Dir = opts.Dir
+	}
+
+	var xml bytes.Buffer
+	copyDone := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(&xml, pr)
+		copyDone <- err
+	}()
+
+	exitCode := 0
+	var exitErr *exec.ExitError
+	runErr := cmd.Run()
+	pw.Close()
+
+	if errors.As(runErr, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	} else if runErr != nil {
+		return nil, fmt.Errorf("valgrind: failed to run: %w", runErr)
+	}
+
+	if err := <-copyDone; err != nil {
+		return nil, fmt.Errorf("valgrind: failed to read report: %w", err)
+	}
+
+	report, err := parseReport(xml.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("valgrind: failed to parse report: %w", err)
+	}
+	report.ExitCode = exitCode
+
+	return report, nil
+}
@@ -0,0 +1,23 @@
+/*
+Package valgrind provides a MemoryChecker implementation that drives the
+Memcheck tool of an installed Valgrind toolchain on the host system.
+
+It registers the valgrind memory checker.
+*/
+package valgrind
+
+import (
+	"os/exec"
+
+	"github.com/tmaxmax/bmoisil/pkg/toolchain"
+)
+
+const checkerName = "valgrind"
+
+var execCommandContext = exec.CommandContext
+
+func init() {
+	toolchain.RegisterMemoryChecker(checkerName, func(pathOrExecutableName string) (toolchain.MemoryChecker, error) {
+		return NewMemoryChecker(pathOrExecutableName)
+	})
+}
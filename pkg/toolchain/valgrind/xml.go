@@ -0,0 +1,98 @@
+package valgrind
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"github.com/tmaxmax/bmoisil/pkg/toolchain"
+)
+
+// xmlOutput mirrors the structure of Valgrind's --xml=yes protocol, keeping only
+// the fields needed to build a toolchain.CheckReport.
+type xmlOutput struct {
+	Errors []xmlError `xml:"error"`
+}
+
+type xmlError struct {
+	Kind  string     `xml:"kind"`
+	What  string     `xml:"what"`
+	XWhat xmlXWhat   `xml:"xwhat"`
+	Stack []xmlFrame `xml:"stack>frame"`
+}
+
+type xmlXWhat struct {
+	Text         string `xml:"text"`
+	LeakedBytes  int64  `xml:"leakedbytes"`
+	LeakedBlocks int    `xml:"leakedblocks"`
+	SuppBytes    int64  `xml:"suppressedbytes"`
+	SuppBlocks   int    `xml:"suppressedblocks"`
+}
+
+type xmlFrame struct {
+	IP   string `xml:"ip"`
+	Obj  string `xml:"obj"`
+	Fn   string `xml:"fn"`
+	Dir  string `xml:"dir"`
+	File string `xml:"file"`
+	Line int    `xml:"line"`
+}
+
+const (
+	kindLeakDefinitelyLost = "Leak_DefinitelyLost"
+	kindLeakIndirectlyLost = "Leak_IndirectlyLost"
+	kindLeakPossiblyLost   = "Leak_PossiblyLost"
+	kindLeakStillReachable = "Leak_StillReachable"
+)
+
+func parseReport(data []byte) (*toolchain.CheckReport, error) {
+	var out xmlOutput
+	if err := xml.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal XML: %w", err)
+	}
+
+	report := &toolchain.CheckReport{}
+
+	for _, e := range out.Errors {
+		switch e.Kind {
+		case kindLeakDefinitelyLost:
+			report.Leak.DefinitelyLost += e.XWhat.LeakedBytes
+		case kindLeakIndirectlyLost:
+			report.Leak.IndirectlyLost += e.XWhat.LeakedBytes
+		case kindLeakPossiblyLost:
+			report.Leak.PossiblyLost += e.XWhat.LeakedBytes
+		case kindLeakStillReachable:
+			report.Leak.StillReachable += e.XWhat.LeakedBytes
+		default:
+			report.Errors = append(report.Errors, toolchain.MemoryError{
+				Kind:  toolchain.MemoryErrorKind(e.Kind),
+				What:  e.What,
+				Stack: parseStack(e.Stack),
+			})
+			continue
+		}
+
+		report.Leak.SuppressedBytes += e.XWhat.SuppBytes
+	}
+
+	return report, nil
+}
+
+func parseStack(frames []xmlFrame) []toolchain.StackFrame {
+	if len(frames) == 0 {
+		return nil
+	}
+
+	out := make([]toolchain.StackFrame, len(frames))
+	for i, f := range frames {
+		out[i] = toolchain.StackFrame{
+			InstructionPointer: f.IP,
+			Object:             f.Obj,
+			Function:           f.Fn,
+			Directory:          f.Dir,
+			File:               f.File,
+			Line:               f.Line,
+		}
+	}
+
+	return out
+}
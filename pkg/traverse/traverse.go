@@ -3,19 +3,60 @@ Package traverse implements tree traversals for the x/net/html parsed HTML trees
 */
 package traverse
 
-import "golang.org/x/net/html"
+import (
+	"container/list"
+	"context"
 
-// Depth performs a depth-first traversal over a parsed HTML document.
-// If the visitor function returns false, traversal is stopped.
-func Depth(root *html.Node, visitor func(*html.Node) bool) {
+	"golang.org/x/net/html"
+)
+
+// Action tells a traversal what to do after a node has been visited.
+type Action int
+
+const (
+	// Continue proceeds with the traversal, including the node's children, in the order the
+	// traversal visits nodes.
+	Continue Action = iota
+	// SkipChildren skips the node's children, but continues with its remaining siblings.
+	SkipChildren
+	// Stop ends the traversal immediately.
+	Stop
+)
+
+// Depth performs a depth-first traversal over a parsed HTML document, calling visitor for
+// every visited node. The Action returned by visitor controls how the traversal proceeds.
+func Depth(root *html.Node, visitor func(*html.Node) Action) {
+	depth(nil, root, visitor)
+}
+
+// DepthFunc is a shim for callers using the older bool-returning visitor contract:
+// returning false stops the traversal, true continues it as Continue would.
+func DepthFunc(root *html.Node, visitor func(*html.Node) bool) {
+	Depth(root, boolAction(visitor))
+}
+
+// DepthWithContext is like Depth, but stops the traversal once ctx is done, checking
+// ctx.Err() between node visits.
+func DepthWithContext(ctx context.Context, root *html.Node, visitor func(*html.Node) Action) {
+	depth(ctx, root, visitor)
+}
+
+func depth(ctx context.Context, root *html.Node, visitor func(*html.Node) Action) {
 	stack := []*html.Node{root}
 
 	for l := len(stack); l > 0; l = len(stack) {
+		if ctx != nil && ctx.Err() != nil {
+			return
+		}
+
 		node := stack[l-1]
 		stack = stack[:l-1]
 
-		if !visitor(node) {
-			break
+		switch visitor(node) {
+		case Stop:
+			return
+		case SkipChildren:
+			continue
 		}
 
 		for next := node.LastChild; next != nil; next = next.PrevSibling {
@@ -23,3 +64,71 @@ func Depth(root *html.Node, visitor func(*html.Node) bool) {
 		}
 	}
 }
+
+// Breadth performs a breadth-first traversal over a parsed HTML document, calling visitor
+// for every visited node. The Action returned by visitor controls how the traversal proceeds.
+func Breadth(root *html.Node, visitor func(*html.Node) Action) {
+	breadth(nil, root, visitor)
+}
+
+// BreadthFunc is a shim for callers using the older bool-returning visitor contract:
+// returning false stops the traversal, true continues it as Continue would.
+func BreadthFunc(root *html.Node, visitor func(*html.Node) bool) {
+	Breadth(root, boolAction(visitor))
+}
+
+// BreadthWithContext is like Breadth, but stops the traversal once ctx is done, checking
+// ctx.Err() between node visits.
+func BreadthWithContext(ctx context.Context, root *html.Node, visitor func(*html.Node) Action) {
+	breadth(ctx, root, visitor)
+}
+
+func breadth(ctx context.Context, root *html.Node, visitor func(*html.Node) Action) {
+	queue := list.New()
+	queue.PushBack(root)
+
+	for queue.Len() > 0 {
+		if ctx != nil && ctx.Err() != nil {
+			return
+		}
+
+		front := queue.Front()
+		queue.Remove(front)
+		node := front.Value.(*html.Node)
+
+		switch visitor(node) {
+		case Stop:
+			return
+		case SkipChildren:
+			continue
+		}
+
+		for next := node.FirstChild; next != nil; next = next.NextSibling {
+			queue.PushBack(next)
+		}
+	}
+}
+
+func boolAction(visitor func(*html.Node) bool) func(*html.Node) Action {
+	return func(n *html.Node) Action {
+		if visitor(n) {
+			return Continue
+		}
+		return Stop
+	}
+}
+
+// AncestorSeq has the same shape as iter.Seq[*html.Node] (a func(yield func(*html.Node) bool)),
+// so it can be ranged over directly on Go 1.23+, once this module's language version allows it.
+type AncestorSeq func(yield func(*html.Node) bool)
+
+// Ancestors returns a sequence of node's ancestors, from its immediate parent up to the root.
+func Ancestors(node *html.Node) AncestorSeq {
+	return func(yield func(*html.Node) bool) {
+		for n := node.Parent; n != nil; n = n.Parent {
+			if !yield(n) {
+				return
+			}
+		}
+	}
+}